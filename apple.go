@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+const (
+	tagAppleHdrImageType      = "8769/927c/000a"
+	tagAppleContentIdentifier = "8769/927c/0011"
+	tagAppleImageCaptureType  = "8769/927c/0014"
+)
+
+// HDR photo is saved together with the original image when "Keep normal photo" is set
+var appleHdrImageTypes = map[int64]string{
+	3: "HDR",
+	4: "Standard",
+}
+
+var appleImageCaptureTypes = map[int64]string{
+	1:  "ProRAW",
+	2:  "Portrait",
+	10: "Photo",
+	11: "Manual Focus",
+	12: "Scene",
+}
+
+// extractApple decodes Live Photo content identifier, HDR image type and capture type from Apple maker notes.
+// Live Photo image and its video have the same content identifier. HDR image type is reported as dynamic range and
+// capture type as scene mode
+func extractApple(tagMap map[string]exif.Tag, exifInfo *ExifInfo) {
+	if !strings.HasPrefix(strings.ToUpper(exifInfo.Make), "APPLE") {
+		return
+	}
+	exifInfo.LivePhotoID = strings.TrimSpace(tagString(tagMap, tagAppleContentIdentifier))
+	if hdrImageType, ok := tagSignedNumber(tagMap, tagAppleHdrImageType); ok {
+		exifInfo.DynamicRange = appleHdrImageTypes[hdrImageType]
+	}
+	if captureType, ok := tagSignedNumber(tagMap, tagAppleImageCaptureType); ok {
+		exifInfo.SceneMode = appleImageCaptureTypes[captureType]
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+func TestExtractApple(t *testing.T) {
+	tests := []struct {
+		name         string
+		order        binary.ByteOrder
+		entries      []tiffEntry
+		livePhotoID  string
+		dynamicRange string
+		sceneMode    string
+	}{
+		{"Live Photo", binary.BigEndian, []tiffEntry{
+			signedLongEntry(exif.AppleHdrImageTypeTagID, 3),
+			asciiEntry(exif.AppleContentIdentifierTagID, "2D3A5F7B-1C4E-4A8B-9F0D-6E2C1B3A4D5E"),
+			signedLongEntry(exif.AppleImageCaptureTypeTagID, 2),
+		}, "2D3A5F7B-1C4E-4A8B-9F0D-6E2C1B3A4D5E", "HDR", "Portrait"},
+		{"little endian maker notes", binary.LittleEndian, []tiffEntry{
+			signedLongEntry(exif.AppleHdrImageTypeTagID, 4),
+			signedLongEntry(exif.AppleImageCaptureTypeTagID, 10),
+		}, "", "Standard", "Photo"},
+		{"unknown values", binary.BigEndian, []tiffEntry{
+			signedLongEntry(exif.AppleHdrImageTypeTagID, 2),
+			signedLongEntry(exif.AppleImageCaptureTypeTagID, 5),
+		}, "", "", ""},
+	}
+	for _, test := range tests {
+		order, entries := test.order, test.entries
+		bom := []byte("MM")
+		if order == binary.LittleEndian {
+			bom = []byte("II")
+		}
+		exifInfo := readTestExif(t, buildMakerNoteJpeg("Apple", "iPhone 12", func(offset uint32) []byte {
+			return buildMakerNote(order, 0, append([]byte("Apple iOS\x00\x00\x01"), bom...), entries...)
+		}))
+		if exifInfo.LivePhotoID != test.livePhotoID {
+			t.Errorf("%s: Live Photo ID '%s' != '%s'", test.name, exifInfo.LivePhotoID, test.livePhotoID)
+		}
+		if exifInfo.DynamicRange != test.dynamicRange {
+			t.Errorf("%s: dynamic range '%s' != '%s'", test.name, exifInfo.DynamicRange, test.dynamicRange)
+		}
+		if exifInfo.SceneMode != test.sceneMode {
+			t.Errorf("%s: scene mode '%s' != '%s'", test.name, exifInfo.SceneMode, test.sceneMode)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+const (
+	tagCanonSerialNumber         = "8769/927c/000c"
+	tagCanonLensModel            = "8769/927c/0095"
+	tagCanonInternalSerialNumber = "8769/927c/0096"
+	tagCanonQuality              = "8769/927c/0001/0003"
+	tagCanonFocusMode            = "8769/927c/0001/0007"
+	tagCanonCameraIso            = "8769/927c/0001/0010"
+	tagCanonLensType             = "8769/927c/0001/0016"
+	tagCanonAutoIso              = "8769/927c/0004/0001"
+	tagCanonBaseIso              = "8769/927c/0004/0002"
+	tagCanonFileInfo             = "8769/927c/0093"
+)
+
+var canonFocusModes = map[uint16]string{
+	0:  "One-shot AF",
+	1:  "AI Servo AF",
+	2:  "AI Focus AF",
+	3:  "Manual Focus",
+	4:  "Single",
+	5:  "Continuous",
+	6:  "Manual Focus",
+	16: "Pan Focus",
+}
+
+// JPEG quality settings
+var canonQualities = map[uint16]string{
+	1: "Economy",
+	2: "Normal",
+	3: "Fine",
+	4: "RAW",
+	5: "Superfine",
+	7: "CRAW",
+}
+
+// ISO values of camera settings for the older cameras, newer cameras store ISO with 0x4000 flag
+var canonCameraIsos = map[uint16]uint16{
+	16: 50,
+	17: 100,
+	18: 200,
+	19: 400,
+	20: 800,
+}
+
+// Canon lens types which can be identified by the lens type number alone (names as listed by ExifTool). Numbers shared
+// between several lenses, i.e. by Canon and Sigma or Tamron lenses, and RF lenses are left out, lens model tag is used
+// for them
+var canonLensTypes = map[uint16]string{
+	1:     "Canon EF 50mm f/1.8",
+	2:     "Canon EF 28mm f/2.8",
+	3:     "Canon EF 135mm f/2.8 Soft",
+	5:     "Canon EF 35-70mm f/3.5-4.5",
+	7:     "Canon EF 100-300mm f/5.6L",
+	11:    "Canon EF 35mm f/2",
+	13:    "Canon EF 15mm f/2.8 Fisheye",
+	14:    "Canon EF 50-200mm f/3.5-4.5L",
+	15:    "Canon EF 50-200mm f/3.5-4.5",
+	16:    "Canon EF 35-135mm f/3.5-4.5",
+	17:    "Canon EF 35-70mm f/3.5-4.5A",
+	18:    "Canon EF 28-70mm f/3.5-4.5",
+	20:    "Canon EF 100-200mm f/4.5A",
+	21:    "Canon EF 80-200mm f/2.8L",
+	23:    "Canon EF 35-105mm f/3.5-4.5",
+	24:    "Canon EF 35-80mm f/4-5.6 Power Zoom",
+	25:    "Canon EF 35-80mm f/4-5.6 Power Zoom",
+	27:    "Canon EF 35-80mm f/4-5.6",
+	29:    "Canon EF 50mm f/1.8 II",
+	30:    "Canon EF 35-105mm f/4.5-5.6",
+	35:    "Canon EF 35-80mm f/4-5.6",
+	36:    "Canon EF 38-76mm f/4.5-5.6",
+	38:    "Canon EF 80-200mm f/4.5-5.6 II",
+	39:    "Canon EF 75-300mm f/4-5.6",
+	40:    "Canon EF 28-80mm f/3.5-5.6",
+	41:    "Canon EF 28-90mm f/4-5.6",
+	43:    "Canon EF 28-105mm f/4-5.6",
+	44:    "Canon EF 90-300mm f/4.5-5.6",
+	45:    "Canon EF-S 18-55mm f/3.5-5.6 [II]",
+	46:    "Canon EF 28-90mm f/4-5.6",
+	48:    "Canon EF-S 18-55mm f/3.5-5.6 IS",
+	49:    "Canon EF-S 55-250mm f/4-5.6 IS",
+	50:    "Canon EF-S 18-200mm f/3.5-5.6 IS",
+	51:    "Canon EF-S 18-135mm f/3.5-5.6 IS",
+	52:    "Canon EF-S 18-55mm f/3.5-5.6 IS II",
+	53:    "Canon EF-S 18-55mm f/3.5-5.6 III",
+	54:    "Canon EF-S 55-250mm f/4-5.6 IS II",
+	94:    "Canon TS-E 17mm f/4L",
+	95:    "Canon TS-E 24mm f/3.5L II",
+	124:   "Canon MP-E 65mm f/2.8 1-5x Macro Photo",
+	125:   "Canon TS-E 24mm f/3.5L",
+	126:   "Canon TS-E 45mm f/2.8",
+	127:   "Canon TS-E 90mm f/2.8",
+	129:   "Canon EF 300mm f/2.8L USM",
+	130:   "Canon EF 50mm f/1.0L USM",
+	132:   "Canon EF 1200mm f/5.6L USM",
+	134:   "Canon EF 600mm f/4L IS USM",
+	135:   "Canon EF 200mm f/1.8L USM",
+	136:   "Canon EF 300mm f/2.8L USM",
+	138:   "Canon EF 28-80mm f/2.8-4L",
+	139:   "Canon EF 400mm f/2.8L USM",
+	140:   "Canon EF 500mm f/4.5L USM",
+	141:   "Canon EF 500mm f/4.5L USM",
+	142:   "Canon EF 300mm f/2.8L IS USM",
+	143:   "Canon EF 500mm f/4L IS USM",
+	144:   "Canon EF 35-135mm f/4-5.6 USM",
+	145:   "Canon EF 100-300mm f/4.5-5.6 USM",
+	146:   "Canon EF 70-210mm f/3.5-4.5 USM",
+	147:   "Canon EF 35-135mm f/4-5.6 USM",
+	148:   "Canon EF 28-80mm f/3.5-5.6 USM",
+	149:   "Canon EF 100mm f/2 USM",
+	151:   "Canon EF 200mm f/2.8L USM",
+	162:   "Canon EF 200mm f/2.8L USM",
+	163:   "Canon EF 300mm f/4L",
+	164:   "Canon EF 400mm f/5.6L",
+	165:   "Canon EF 70-200mm f/2.8L USM",
+	166:   "Canon EF 70-200mm f/2.8L USM + 1.4x",
+	167:   "Canon EF 70-200mm f/2.8L USM + 2x",
+	171:   "Canon EF 300mm f/4L USM",
+	175:   "Canon EF 400mm f/2.8L USM",
+	176:   "Canon EF 24-85mm f/3.5-4.5 USM",
+	177:   "Canon EF 300mm f/4L IS USM",
+	178:   "Canon EF 28-135mm f/3.5-5.6 IS",
+	179:   "Canon EF 24mm f/1.4L USM",
+	184:   "Canon EF 400mm f/2.8L USM + 2x",
+	185:   "Canon EF 600mm f/4L IS USM",
+	186:   "Canon EF 70-200mm f/4L USM",
+	187:   "Canon EF 70-200mm f/4L USM + 1.4x",
+	188:   "Canon EF 70-200mm f/4L USM + 2x",
+	189:   "Canon EF 70-200mm f/4L USM + 2.8x",
+	190:   "Canon EF 100mm f/2.8 Macro USM",
+	191:   "Canon EF 400mm f/4 DO IS",
+	193:   "Canon EF 35-80mm f/4-5.6 USM",
+	194:   "Canon EF 80-200mm f/4.5-5.6 USM",
+	195:   "Canon EF 35-105mm f/4.5-5.6 USM",
+	196:   "Canon EF 75-300mm f/4-5.6 USM",
+	199:   "Canon EF 28-80mm f/3.5-5.6 USM",
+	200:   "Canon EF 75-300mm f/4-5.6 USM",
+	201:   "Canon EF 28-80mm f/3.5-5.6 USM",
+	202:   "Canon EF 28-80mm f/3.5-5.6 USM IV",
+	208:   "Canon EF 22-55mm f/4-5.6 USM",
+	209:   "Canon EF 55-200mm f/4.5-5.6",
+	210:   "Canon EF 28-90mm f/4-5.6 USM",
+	211:   "Canon EF 28-200mm f/3.5-5.6 USM",
+	212:   "Canon EF 28-105mm f/4-5.6 USM",
+	214:   "Canon EF-S 18-55mm f/3.5-5.6 USM",
+	215:   "Canon EF 55-200mm f/4.5-5.6 II USM",
+	224:   "Canon EF 70-200mm f/2.8L IS USM",
+	225:   "Canon EF 70-200mm f/2.8L IS USM + 1.4x",
+	226:   "Canon EF 70-200mm f/2.8L IS USM + 2x",
+	227:   "Canon EF 70-200mm f/2.8L IS USM + 2.8x",
+	228:   "Canon EF 28-105mm f/3.5-4.5 USM",
+	229:   "Canon EF 16-35mm f/2.8L USM",
+	230:   "Canon EF 24-70mm f/2.8L USM",
+	231:   "Canon EF 17-40mm f/4L USM",
+	232:   "Canon EF 70-300mm f/4.5-5.6 DO IS USM",
+	233:   "Canon EF 28-300mm f/3.5-5.6L IS USM",
+	235:   "Canon EF-S 10-22mm f/3.5-4.5 USM",
+	236:   "Canon EF-S 60mm f/2.8 Macro USM",
+	237:   "Canon EF 24-105mm f/4L IS USM",
+	238:   "Canon EF 70-300mm f/4-5.6 IS USM",
+	241:   "Canon EF 50mm f/1.2L USM",
+	242:   "Canon EF 70-200mm f/4L IS USM",
+	243:   "Canon EF 70-200mm f/4L IS USM + 1.4x",
+	244:   "Canon EF 70-200mm f/4L IS USM + 2x",
+	245:   "Canon EF 70-200mm f/4L IS USM + 2.8x",
+	246:   "Canon EF 16-35mm f/2.8L II USM",
+	247:   "Canon EF 14mm f/2.8L II USM",
+	249:   "Canon EF 800mm f/5.6L IS USM",
+	251:   "Canon EF 70-200mm f/2.8L IS II USM",
+	252:   "Canon EF 70-200mm f/2.8L IS II USM + 1.4x",
+	253:   "Canon EF 70-200mm f/2.8L IS II USM + 2x",
+	488:   "Canon EF-S 15-85mm f/3.5-5.6 IS USM",
+	489:   "Canon EF 70-300mm f/4-5.6L IS USM",
+	490:   "Canon EF 8-15mm f/4L Fisheye USM",
+	492:   "Canon EF 400mm f/2.8L IS II USM",
+	494:   "Canon EF 600mm f/4L IS II USM",
+	496:   "Canon EF 200-400mm f/4L IS USM",
+	499:   "Canon EF 200-400mm f/4L IS USM + 1.4x",
+	503:   "Canon EF 24mm f/2.8 IS USM",
+	504:   "Canon EF 24-70mm f/4L IS USM",
+	505:   "Canon EF 35mm f/2 IS USM",
+	506:   "Canon EF 400mm f/4 DO IS II USM",
+	507:   "Canon EF 16-35mm f/4L IS USM",
+	751:   "Canon EF 16-35mm f/2.8L III USM",
+	752:   "Canon EF 24-105mm f/4L IS II USM",
+	753:   "Canon EF 85mm f/1.4L IS USM",
+	754:   "Canon EF 70-200mm f/4L IS II USM",
+	757:   "Canon EF 400mm f/2.8L IS III USM",
+	758:   "Canon EF 600mm f/4L IS III USM",
+	4142:  "Canon EF-S 18-135mm f/3.5-5.6 IS STM",
+	4144:  "Canon EF 40mm f/2.8 STM",
+	4145:  "Canon EF-M 22mm f/2 STM",
+	4146:  "Canon EF-S 18-55mm f/3.5-5.6 IS STM",
+	4147:  "Canon EF-M 11-22mm f/4-5.6 IS STM",
+	4148:  "Canon EF-S 55-250mm f/4-5.6 IS STM",
+	4149:  "Canon EF-M 55-200mm f/4.5-6.3 IS STM",
+	4150:  "Canon EF-S 10-18mm f/4.5-5.6 IS STM",
+	4152:  "Canon EF 24-105mm f/3.5-5.6 IS STM",
+	4153:  "Canon EF-M 15-45mm f/3.5-6.3 IS STM",
+	4154:  "Canon EF-S 24mm f/2.8 STM",
+	4155:  "Canon EF-M 28mm f/3.5 Macro IS STM",
+	4156:  "Canon EF 50mm f/1.8 STM",
+	4157:  "Canon EF-M 18-150mm f/3.5-6.3 IS STM",
+	4158:  "Canon EF-S 18-55mm f/4-5.6 IS STM",
+	4159:  "Canon EF-M 32mm f/1.4 STM",
+	4160:  "Canon EF-S 35mm f/2.8 Macro IS STM",
+	36910: "Canon EF 70-300mm f/4-5.6 IS II USM",
+	36912: "Canon EF-S 18-135mm f/3.5-5.6 IS USM",
+}
+
+// file info of 1D Mark II bodies keeps shutter count as two words in swapped order, other bodies store the file number
+// there (see ShutterCount of Canon FileInfo tags in ExifTool)
+var canonFileInfoShutterCount = regexp.MustCompile(`\b1Ds? Mark II\b`)
+
+// Canon ISO values in shot info are stored as APEX-like values
+func canonIsoValue(value uint16) float64 {
+	return math.Exp(float64(int16(value))/32*math.Ln2) * 100
+}
+
+// extractCanon decodes Canon maker notes: lens, serial number, focus mode, quality setting, ISO and shutter count
+func extractCanon(tagMap map[string]exif.Tag, exifInfo *ExifInfo) {
+	if !strings.HasPrefix(strings.ToUpper(exifInfo.Make), "CANON") {
+		return
+	}
+	if len(exifInfo.LensModel) == 0 {
+		if lens := strings.TrimSpace(tagString(tagMap, tagCanonLensModel)); len(lens) > 0 {
+			exifInfo.LensModel = lens
+		} else if lens, ok := canonLensTypes[tagShort(tagMap, tagCanonLensType)]; ok {
+			exifInfo.LensModel = lens
+		}
+	}
+	if len(exifInfo.SerialNumber) == 0 {
+		if tag, ok := tagMap[tagCanonSerialNumber]; ok {
+			if serial, ok := tag.Value.([]uint32); ok && len(serial) > 0 {
+				exifInfo.SerialNumber = fmt.Sprintf("%d", serial[0])
+			}
+		} else if serial := strings.TrimSpace(tagString(tagMap, tagCanonInternalSerialNumber)); len(serial) > 0 {
+			exifInfo.SerialNumber = serial
+		}
+	}
+	if _, ok := tagMap[tagCanonFocusMode]; ok {
+		if focusMode, ok := canonFocusModes[tagShort(tagMap, tagCanonFocusMode)]; ok {
+			exifInfo.FocusMode = focusMode
+		}
+	}
+	if _, ok := tagMap[tagCanonQuality]; ok {
+		exifInfo.QualitySetting = canonQualities[tagShort(tagMap, tagCanonQuality)]
+	}
+	if exifInfo.Iso == 0 {
+		extractCanonIso(tagMap, exifInfo)
+	}
+	if canonFileInfoShutterCount.MatchString(exifInfo.Model) {
+		if tag, ok := tagMap[tagCanonFileInfo]; ok {
+			if values, ok := tag.Value.([]uint16); ok && len(values) > 2 {
+				exifInfo.ShutterCount = uint32(values[1])<<16 | uint32(values[2])
+			}
+		}
+	}
+}
+
+// internal ISO is calculated from base ISO and auto ISO of shot info, ISO from camera settings is used as a fallback
+func extractCanonIso(tagMap map[string]exif.Tag, exifInfo *ExifInfo) {
+	if _, ok := tagMap[tagCanonBaseIso]; ok {
+		baseIso := canonIsoValue(tagShort(tagMap, tagCanonBaseIso)) / 32
+		autoIso := 100.0
+		if _, ok := tagMap[tagCanonAutoIso]; ok {
+			autoIso = canonIsoValue(tagShort(tagMap, tagCanonAutoIso))
+		}
+		if iso := math.Round(baseIso * autoIso / 100); iso > 0 && iso < math.MaxUint16 {
+			exifInfo.Iso = uint16(iso)
+			return
+		}
+	}
+	cameraIso := tagShort(tagMap, tagCanonCameraIso)
+	if cameraIso&0x4000 != 0 {
+		exifInfo.Iso = cameraIso & 0x3FFF
+	} else if iso, ok := canonCameraIsos[cameraIso]; ok {
+		exifInfo.Iso = iso
+	}
+}
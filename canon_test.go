@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+// canonArray creates Canon array entry with the given values at their indexes, other values are zero
+func canonArray(id uint16, size int, values map[int]uint16) tiffEntry {
+	array := make([]uint16, size)
+	for index, value := range values {
+		array[index] = value
+	}
+	return shortEntry(id, array...)
+}
+
+func TestExtractCanon(t *testing.T) {
+	tests := []struct {
+		name      string
+		make      string
+		entries   []tiffEntry
+		lens      string
+		serial    string
+		focusMode string
+		quality   string
+		iso       uint16
+	}{
+		{"lens model and serial number", "Canon", []tiffEntry{
+			longEntry(exif.CanonSerialNumberTagID, 123456789),
+			asciiEntry(exif.CanonLensModelTagID, "RF24-105mm F4 L IS USM"),
+		}, "RF24-105mm F4 L IS USM", "123456789", "", "", 0},
+		{"camera settings", "Canon", []tiffEntry{
+			canonArray(exif.CanonCameraSettingsTagID, 47, map[int]uint16{3: 3, 7: 1, 22: 1}),
+			asciiEntry(exif.CanonInternalSerialNumberTagID, "H1234567 "),
+		}, "Canon EF 50mm f/1.8", "H1234567", "AI Servo AF", "Fine", 0},
+		{"lens type shared by several lenses", "Canon", []tiffEntry{
+			canonArray(exif.CanonCameraSettingsTagID, 47, map[int]uint16{22: 137}),
+		}, "", "", "One-shot AF", "", 0},
+		{"STM lens type", "Canon", []tiffEntry{
+			canonArray(exif.CanonCameraSettingsTagID, 47, map[int]uint16{22: 4146}),
+		}, "Canon EF-S 18-55mm f/3.5-5.6 IS STM", "", "One-shot AF", "", 0},
+		// base ISO 160 is 100 * 2^(160/32) / 32 = ISO 100, auto ISO 32 is 100 * 2^(32/32) = 200%
+		{"base and auto ISO", "Canon", []tiffEntry{
+			canonArray(exif.CanonShotInfoTagID, 34, map[int]uint16{1: 32, 2: 160}),
+		}, "", "", "", "", 200},
+		{"camera ISO flag", "Canon", []tiffEntry{
+			canonArray(exif.CanonCameraSettingsTagID, 47, map[int]uint16{16: 0x4000 | 3200}),
+		}, "", "", "One-shot AF", "", 3200},
+		{"camera ISO of older cameras", "Canon", []tiffEntry{
+			canonArray(exif.CanonCameraSettingsTagID, 47, map[int]uint16{3: 2, 16: 19}),
+		}, "", "", "One-shot AF", "Normal", 400},
+		{"other make", "OtherMake", []tiffEntry{
+			asciiEntry(exif.CanonLensModelTagID, "RF24-105mm F4 L IS USM"),
+			canonArray(exif.CanonCameraSettingsTagID, 47, map[int]uint16{3: 3, 16: 19}),
+		}, "", "", "", "", 0},
+	}
+	for _, test := range tests {
+		entries := test.entries
+		exifInfo := readTestExif(t, buildMakerNoteJpeg(test.make, "Canon EOS R6", func(offset uint32) []byte {
+			return buildMakerNote(binary.BigEndian, offset, nil, entries...)
+		}))
+		if exifInfo.LensModel != test.lens {
+			t.Errorf("%s: lens model '%s' != '%s'", test.name, exifInfo.LensModel, test.lens)
+		}
+		if exifInfo.SerialNumber != test.serial {
+			t.Errorf("%s: serial number '%s' != '%s'", test.name, exifInfo.SerialNumber, test.serial)
+		}
+		if exifInfo.FocusMode != test.focusMode {
+			t.Errorf("%s: focus mode '%s' != '%s'", test.name, exifInfo.FocusMode, test.focusMode)
+		}
+		if exifInfo.QualitySetting != test.quality {
+			t.Errorf("%s: quality setting '%s' != '%s'", test.name, exifInfo.QualitySetting, test.quality)
+		}
+		if exifInfo.Iso != test.iso {
+			t.Errorf("%s: ISO %d != %d", test.name, exifInfo.Iso, test.iso)
+		}
+	}
+}
+
+func TestExtractCanonShutterCount(t *testing.T) {
+	tests := []struct {
+		model        string
+		shutterCount uint32
+	}{
+		{"Canon EOS-1D Mark II", 0x00012345},
+		{"Canon EOS-1Ds Mark II", 0x00012345},
+		// other bodies store file number in file info
+		{"Canon EOS 30D", 0},
+	}
+	for _, test := range tests {
+		exifInfo := readTestExif(t, buildMakerNoteJpeg("Canon", test.model, func(offset uint32) []byte {
+			return buildMakerNote(binary.BigEndian, offset, nil,
+				canonArray(exif.CanonFileInfoTagID, 10, map[int]uint16{1: 0x0001, 2: 0x2345}))
+		}))
+		if exifInfo.ShutterCount != test.shutterCount {
+			t.Errorf("%s: shutter count %d != %d", test.model, exifInfo.ShutterCount, test.shutterCount)
+		}
+	}
+}
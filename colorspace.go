@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+const (
+	tagColorSpace   = "8769/a001"
+	tagInteropIndex = "8769/a005/0001"
+
+	colorSpaceSRGB         = "sRGB"
+	colorSpaceAdobeRGB     = "Adobe RGB"
+	colorSpaceDisplayP3    = "Display P3"
+	colorSpaceUncalibrated = "Uncalibrated"
+)
+
+var exifColorSpaces = map[uint16]string{
+	0x1:    colorSpaceSRGB,
+	0x2:    colorSpaceAdobeRGB, // not in the standard, but used by some cameras
+	0xFFFF: colorSpaceUncalibrated,
+}
+
+// DCF interoperability index tells the colour space of uncalibrated images
+var interopColorSpaces = map[string]string{
+	"R98": colorSpaceSRGB,
+	"R03": colorSpaceAdobeRGB,
+}
+
+// normalizes names of the well-known ICC profiles, descriptions of other profiles are returned as is
+func iccColorSpace(description string) string {
+	switch {
+	case strings.Contains(description, "sRGB"):
+		return colorSpaceSRGB
+	case strings.Contains(description, "Adobe RGB"):
+		return colorSpaceAdobeRGB
+	case strings.Contains(description, "P3"):
+		return colorSpaceDisplayP3
+	}
+	return strings.TrimSpace(description)
+}
+
+// extractColorSpace works out colour space of the image. Embedded ICC profile is the most precise source, then
+// Exif ColorSpace tag and the interoperability index for uncalibrated images
+func extractColorSpace(tagMap map[string]exif.Tag, iccProfile *exif.IccProfile, exifInfo *ExifInfo) {
+	if iccProfile != nil && len(iccProfile.Description) > 0 {
+		exifInfo.ColorSpace = iccColorSpace(iccProfile.Description)
+		return
+	}
+	colorSpace, ok := exifColorSpaces[tagShort(tagMap, tagColorSpace)]
+	if interopColorSpace, found := interopColorSpaces[strings.TrimSpace(tagString(tagMap, tagInteropIndex))]; found {
+		if !ok || colorSpace == colorSpaceUncalibrated {
+			colorSpace, ok = interopColorSpace, true
+		}
+	}
+	if ok {
+		exifInfo.ColorSpace = colorSpace
+	}
+}
@@ -13,15 +13,59 @@ import (
 var supportedFiles = map[string]bool{
 	".jpg":  true,
 	".jpeg": true,
+	".mpo":  true,
+	".nef":  true,
+	".arw":  true,
+	".dng":  true,
+	".orf":  true,
+	".pef":  true,
+	".cr2":  true,
+	".rw2":  true,
+	".raf":  true,
+	".cr3":  true,
+	".heic": true,
+	".heif": true,
+	".avif": true,
+	".png":  true,
+	".webp": true,
+	".jxl":  true,
+}
+
+var videoFiles = map[string]bool{
+	".mp4": true,
+	".mov": true,
+}
+
+const (
+	mediaTypeImage = "image"
+	mediaTypeVideo = "video"
+)
+
+// ImageFile is an image found by the scanner together with its XMP sidecar file, if any
+type ImageFile struct {
+	Path        string
+	SidecarPath string
+}
+
+// sidecar files are named either IMG_1234.xmp or IMG_1234.CR2.xmp
+func findSidecar(path string) string {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	for _, candidate := range []string{base + ".xmp", base + ".XMP", path + ".xmp", path + ".XMP"} {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
 }
 
 func isSupportedFile(path string) bool {
-	_, ok := supportedFiles[strings.ToLower(filepath.Ext(path))]
-	return ok
+	ext := strings.ToLower(filepath.Ext(path))
+	_, ok := supportedFiles[ext]
+	return ok || videoFiles[ext]
 }
 
 // ListImages lists all the supported images in given path. includes images in subdirectories
-func ListImages(path string, wg *sync.WaitGroup, paths chan string) {
+func ListImages(path string, wg *sync.WaitGroup, paths chan ImageFile) {
 	defer close(paths)
 	defer wg.Done()
 
@@ -29,7 +73,7 @@ func ListImages(path string, wg *sync.WaitGroup, paths chan string) {
 		if info.IsDir() {
 			fmt.Printf("%s%s\r", utils.Shorten(path), utils.ClearLine)
 		} else if !info.IsDir() && isSupportedFile(path) && filepath.Base(path)[0] != '.' {
-			paths <- path
+			paths <- ImageFile{Path: path, SidecarPath: findSidecar(path)}
 		}
 		return nil
 	})
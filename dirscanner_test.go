@@ -1,21 +1,28 @@
 package main
 
 import (
+	"sync"
 	"testing"
 )
 
 // TestListImages tests ligst images
 func TestListImages(t *testing.T) {
-	paths, err := ListImages("test-data/scan")
-	if err != nil {
-		t.Errorf("ListImages returned error %s", err)
+	var wg sync.WaitGroup
+	images := make(chan ImageFile)
+	wg.Add(1)
+	go ListImages("test-data/scan", &wg, images)
+	paths := make([]string, 0)
+	for image := range images {
+		paths = append(paths, image.Path)
 	}
+	wg.Wait()
 	var expected = map[string]bool{
 		"test-data/scan/DSC_0352.jpg":                       true,
 		"test-data/scan/P1020297.JPG":                       true,
 		"test-data/scan/_DSC0958.jpg":                       true,
 		"test-data/scan/subdir/DSC_3455.JPG":                true,
 		"test-data/scan/subdir/P1020630.jpg":                true,
+		"test-data/scan/subdir/ignore-me.png":               true,
 		"test-data/scan/subdir/triple-nested/DSC_9068.jpg":  true,
 		"test-data/scan/subdir/triple-nested/P1030129.jpeg": true,
 	}
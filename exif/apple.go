@@ -0,0 +1,59 @@
+package exif
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Apple maker notes tags
+const (
+	AppleMakerNoteVersionTagID      = 0x0001
+	AppleAccelerationVectorTagID    = 0x0008
+	AppleHdrImageTypeTagID          = 0x000a
+	AppleBurstUUIDTagID             = 0x000b
+	AppleContentIdentifierTagID     = 0x0011
+	AppleImageCaptureTypeTagID      = 0x0014
+	AppleImageUniqueIDTagID         = 0x0015
+	AppleLivePhotoVideoIndexTagID   = 0x0017
+	ApplePhotosAppFeatureFlagsTagID = 0x001f
+)
+
+// Apple maker notes start with "Apple iOS\0" followed by version and byte order mark, IFD follows 14 bytes header.
+// Offsets are relative to the maker notes
+var appleHeader = []byte("Apple iOS\x00")
+
+const appleHeaderSize = 14
+
+var appleTagNames = map[uint16]string{
+	AppleMakerNoteVersionTagID:      "MakerNoteVersion",
+	AppleAccelerationVectorTagID:    "AccelerationVector",
+	AppleHdrImageTypeTagID:          "HDRImageType",
+	AppleBurstUUIDTagID:             "BurstUUID",
+	AppleContentIdentifierTagID:     "ContentIdentifier",
+	AppleImageCaptureTypeTagID:      "ImageCaptureType",
+	AppleImageUniqueIDTagID:         "ImageUniqueID",
+	AppleLivePhotoVideoIndexTagID:   "LivePhotoVideoIndex",
+	ApplePhotosAppFeatureFlagsTagID: "PhotosAppFeatureFlags",
+}
+
+func init() {
+	for id, name := range appleTagNames {
+		tagNames[fmt.Sprintf("%04x/%04x/%04x", exifTagID, makerNotesTagID, id)] = "Apple " + name
+	}
+}
+
+func appleDetector(data []byte) bool {
+	return bytes.HasPrefix(data, appleHeader)
+}
+
+func appleReader(file File, entry ifdEntry) (*ifd, error) {
+	if len(entry.ValueBytes) < appleHeaderSize {
+		return nil, nil
+	}
+	order := entry.ValueBytes[appleHeaderSize-2]
+	if order != LittleEndian && order != BigEndian {
+		return nil, fmt.Errorf("Invalid byte order of Apple maker notes: %x", order)
+	}
+	makerNoteOffset := file.GetTiffHeaderOffset() + int64(entry.Data)
+	return readRelativeIfd(file, makerNoteOffset, appleHeaderSize, order, entry.IfdIndex)
+}
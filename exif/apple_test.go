@@ -0,0 +1,55 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestReadingAppleMakerNotes(t *testing.T) {
+	hdrImageType := longEntry(AppleHdrImageTypeTagID, 3)
+	hdrImageType.Type = TypeSignedLong
+	acceleration := rationalEntry(AppleAccelerationVectorTagID, 0xFFFFFFFF, 100, 98, 100, 0, 1)
+	acceleration.Type = TypeSignedRational
+	makerNote := buildMakerNote(binary.BigEndian, []byte("Apple iOS\x00\x00\x01MM"), []tiffEntry{
+		acceleration,
+		hdrImageType,
+		asciiEntry(AppleContentIdentifierTagID, "6F1D23A5-9C4B-4E4B-A5E8-F3A2C1B0D9E8"),
+	})
+	tiff := buildTiffWithOrder(binary.LittleEndian, []tiffEntry{
+		asciiEntry(0x010f, "Apple"),
+		{ID: exifTagID, Sub: []tiffEntry{
+			undefinedEntry(makerNotesTagID, makerNote),
+		}},
+	})
+	tagMap := readTestTags(t, writeTempFile(t, ".jpg", buildJpeg(tiff)))
+
+	if tag, ok := tagMap["8769/927c/0011"]; !ok || tag.Value.(string) != "6F1D23A5-9C4B-4E4B-A5E8-F3A2C1B0D9E8" || tag.Name() != "Apple ContentIdentifier" {
+		t.Fatalf("Failed to read Apple content identifier")
+	}
+	if tag, ok := tagMap["8769/927c/000a"]; !ok || tag.Value.([]int32)[0] != 3 {
+		t.Fatalf("Failed to read Apple HDR image type")
+	}
+	if tag, ok := tagMap["8769/927c/0008"]; !ok || len(tag.Value.([]SignedRational)) != 3 || tag.Value.([]SignedRational)[0].Numerator != -1 {
+		t.Fatalf("Failed to read Apple acceleration vector")
+	}
+}
+
+func TestReadingLivePhotoVideo(t *testing.T) {
+	meta := buildBox("meta",
+		buildBox("hdlr", make([]byte, 24)),
+		buildKeys("com.apple.quicktime.content.identifier"),
+		buildBox("ilst", buildBox(string([]byte{0, 0, 0, 1}), buildDataBox("6F1D23A5-9C4B-4E4B-A5E8-F3A2C1B0D9E8"))),
+	)
+	mov := bytes.Join([][]byte{
+		buildFtyp("qt  "),
+		buildBox("moov", buildMvhd(time.Date(2021, 1, 9, 10, 25, 9, 0, time.UTC)), meta),
+		buildBox("mdat", make([]byte, 16)),
+	}, nil)
+
+	tagMap := readTestTags(t, writeTempFile(t, ".mov", mov))
+	if tag, ok := tagMap["8769/927c/0011"]; !ok || tag.Value.(string) != "6F1D23A5-9C4B-4E4B-A5E8-F3A2C1B0D9E8" {
+		t.Fatalf("Failed to read content identifier from metadata keys: %v", tagMap)
+	}
+}
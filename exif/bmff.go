@@ -0,0 +1,146 @@
+package exif
+
+import (
+	"fmt"
+)
+
+// bmffBox is a box of ISO base media file format, which is used by MP4, QuickTime, HEIF and CR3 files
+type bmffBox struct {
+	Type string
+	// hex representation of the extended type of "uuid" boxes
+	UserType string
+	// offset of the box header
+	Offset int64
+	// offset of the box payload
+	Start int64
+	// offset of the first byte after the box
+	End int64
+}
+
+// bmffDetector creates detector that recognizes ISO BMFF files with one of the given major brands in "ftyp" box
+func bmffDetector(brands ...string) func([]byte) bool {
+	return func(header []byte) bool {
+		if string(header[4:8]) != "ftyp" {
+			return false
+		}
+		for _, brand := range brands {
+			if string(header[8:12]) == brand {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// readBox reads box header at the current position. end is the offset of the end of the parent box or file
+func readBox(file File, end int64) (*bmffBox, error) {
+	offset, err := file.currentPosition()
+	if err != nil {
+		return nil, err
+	}
+	size32, err := file.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	boxType, err := file.readBytes(4)
+	if err != nil {
+		return nil, err
+	}
+	size := int64(size32)
+	headerSize := int64(8)
+	if size32 == 1 { // 64-bit box size follows box type
+		var size64 uint64
+		err = file.Read(&size64)
+		if err != nil {
+			return nil, err
+		}
+		size = int64(size64)
+		headerSize += 8
+	} else if size32 == 0 { // box extends to the end of the parent
+		size = end - offset
+	}
+	box := &bmffBox{
+		Type:   string(boxType),
+		Offset: offset,
+		Start:  offset + headerSize,
+		End:    offset + size,
+	}
+	if box.Type == "uuid" {
+		userType, err := file.readBytes(16)
+		if err != nil {
+			return nil, err
+		}
+		box.UserType = fmt.Sprintf("%x", userType)
+		box.Start += 16
+	}
+	if box.End < box.Start || box.End > end {
+		return nil, fmt.Errorf("Invalid size of '%s' box at %d in %s", box.Type, offset, file.GetPath())
+	}
+	return box, nil
+}
+
+// readBoxes reads headers of all the boxes between start and end offsets
+func readBoxes(file File, start int64, end int64) ([]bmffBox, error) {
+	file.SetOrder(BigEndian) // box headers are always big endian
+	boxes := make([]bmffBox, 0)
+	pos := start
+	for pos+8 <= end {
+		_, err := file.seek(pos)
+		if err != nil {
+			return nil, err
+		}
+		box, err := readBox(file, end)
+		if err != nil {
+			return nil, err
+		}
+		boxes = append(boxes, *box)
+		pos = box.End
+	}
+	return boxes, nil
+}
+
+// readChildBoxes reads headers of all the boxes contained in the box
+func readChildBoxes(file File, box *bmffBox) ([]bmffBox, error) {
+	return readBoxes(file, box.Start, box.End)
+}
+
+// readTopLevelBoxes reads headers of all the top level boxes in the file
+func readTopLevelBoxes(file File) ([]bmffBox, error) {
+	size, err := file.size()
+	if err != nil {
+		return nil, err
+	}
+	return readBoxes(file, 0, size)
+}
+
+func findBox(boxes []bmffBox, boxType string) *bmffBox {
+	for _, box := range boxes {
+		if box.Type == boxType {
+			return &box
+		}
+	}
+	return nil
+}
+
+func findUUIDBox(boxes []bmffBox, userType string) *bmffBox {
+	for _, box := range boxes {
+		if box.Type == "uuid" && box.UserType == userType {
+			return &box
+		}
+	}
+	return nil
+}
+
+// readBoxData reads the whole payload of the box
+func readBoxData(file File, box *bmffBox) ([]byte, error) {
+	_, err := file.seek(box.Start)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, box.End-box.Start)
+	err = file.Read(data)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,83 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+)
+
+// buildBox creates ISO BMFF box with given type and payload
+func buildBox(boxType string, payload ...[]byte) []byte {
+	var buf bytes.Buffer
+	size := 8
+	for _, p := range payload {
+		size += len(p)
+	}
+	binary.Write(&buf, binary.BigEndian, uint32(size))
+	buf.WriteString(boxType)
+	for _, p := range payload {
+		buf.Write(p)
+	}
+	return buf.Bytes()
+}
+
+func buildUUIDBox(userType string, payload ...[]byte) []byte {
+	uuid, _ := hex.DecodeString(userType)
+	return buildBox("uuid", append([][]byte{uuid}, payload...)...)
+}
+
+func buildFtyp(brand string) []byte {
+	return buildBox("ftyp", []byte(brand), []byte{0, 0, 0, 0}, []byte(brand))
+}
+
+func TestReadingBoxes(t *testing.T) {
+	data := bytes.Join([][]byte{
+		buildFtyp("isom"),
+		buildBox("moov", buildBox("mvhd", make([]byte, 100)), buildUUIDBox(canonUUID, []byte{1, 2, 3})),
+		buildBox("mdat", make([]byte, 16)),
+	}, nil)
+	file := newExifFileBytes("test", data)
+
+	boxes, err := readTopLevelBoxes(file)
+	if err != nil {
+		t.Fatalf("Failed to read boxes: %v", err)
+	}
+	if len(boxes) != 3 || boxes[0].Type != "ftyp" || boxes[1].Type != "moov" || boxes[2].Type != "mdat" {
+		t.Fatalf("Invalid top level boxes: %v", boxes)
+	}
+	children, err := readChildBoxes(file, findBox(boxes, "moov"))
+	if err != nil {
+		t.Fatalf("Failed to read moov children: %v", err)
+	}
+	uuid := findUUIDBox(children, canonUUID)
+	if uuid == nil {
+		t.Fatalf("Failed to find uuid box: %v", children)
+	}
+	payload, err := readBoxData(file, uuid)
+	if err != nil || !bytes.Equal(payload, []byte{1, 2, 3}) {
+		t.Fatalf("Invalid uuid box payload: %v", payload)
+	}
+}
+
+func TestReadingCr3(t *testing.T) {
+	cmt1 := buildTiffWithOrder(binary.LittleEndian, []tiffEntry{
+		asciiEntry(0x010f, "Canon"),
+		asciiEntry(0x0110, "Canon EOS R6"),
+	})
+	cmt2 := buildTiffWithOrder(binary.LittleEndian, []tiffEntry{
+		shortEntry(0x8827, 200),
+	})
+	data := bytes.Join([][]byte{
+		buildFtyp("crx "),
+		buildBox("moov", buildUUIDBox(canonUUID, buildBox("CMT1", cmt1), buildBox("CMT2", cmt2))),
+	}, nil)
+
+	tagMap := readTestTags(t, writeTempFile(t, ".cr3", data))
+	if tag, ok := tagMap[model]; !ok || tag.Value.(string) != "Canon EOS R6" {
+		t.Fatalf("Failed to read model from CMT1: %v", tagMap)
+	}
+	if tag, ok := tagMap[iso]; !ok || tag.Value.([]uint16)[0] != 200 {
+		t.Fatalf("Failed to read ISO from CMT2: %v", tagMap)
+	}
+}
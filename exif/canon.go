@@ -0,0 +1,145 @@
+package exif
+
+import (
+	"fmt"
+)
+
+// Canon maker notes tags
+const (
+	CanonCameraSettingsTagID       = 0x0001
+	CanonShotInfoTagID             = 0x0004
+	CanonSerialNumberTagID         = 0x000c
+	CanonFileInfoTagID             = 0x0093
+	CanonLensModelTagID            = 0x0095
+	CanonInternalSerialNumberTagID = 0x0096
+)
+
+// names of the values of Canon arrays. Each value of the array is exposed as a separate tag with array tag ID in
+// the path, i.e. lens type from camera settings has path "8769/927c/0001/0016"
+var canonArrays = map[uint16]map[uint16]string{
+	CanonCameraSettingsTagID: {
+		1:  "MacroMode",
+		2:  "SelfTimer",
+		3:  "Quality",
+		4:  "FlashMode",
+		5:  "ContinuousDrive",
+		7:  "FocusMode",
+		9:  "RecordMode",
+		10: "ImageSize",
+		11: "EasyMode",
+		12: "DigitalZoom",
+		13: "Contrast",
+		14: "Saturation",
+		15: "Sharpness",
+		16: "CameraISO",
+		17: "MeteringMode",
+		18: "FocusRange",
+		19: "AFPoint",
+		20: "ExposureMode",
+		22: "LensType",
+		23: "MaxFocalLength",
+		24: "MinFocalLength",
+		25: "FocalUnits",
+		26: "MaxAperture",
+		27: "MinAperture",
+		28: "FlashActivity",
+		29: "FlashBits",
+		32: "FocusContinuous",
+		33: "AESetting",
+		34: "ImageStabilization",
+		35: "DisplayAperture",
+		36: "ZoomSourceWidth",
+		37: "ZoomTargetWidth",
+		39: "SpotMeteringMode",
+		40: "PhotoEffect",
+		41: "ManualFlashOutput",
+		42: "ColorTone",
+		46: "SRAWQuality",
+	},
+	CanonShotInfoTagID: {
+		1:  "AutoISO",
+		2:  "BaseISO",
+		3:  "MeasuredEV",
+		4:  "TargetAperture",
+		5:  "TargetExposureTime",
+		6:  "ExposureCompensation",
+		7:  "WhiteBalance",
+		8:  "SlowShutter",
+		9:  "SequenceNumber",
+		10: "OpticalZoomCode",
+		12: "CameraTemperature",
+		13: "FlashGuideNumber",
+		14: "AFPointsInFocus",
+		15: "FlashExposureComp",
+		16: "AutoExposureBracketing",
+		17: "AEBBracketValue",
+		18: "ControlMode",
+		19: "FocusDistanceUpper",
+		20: "FocusDistanceLower",
+		21: "FNumber",
+		22: "ExposureTime",
+		23: "MeasuredEV2",
+		24: "BulbDuration",
+		26: "CameraType",
+		27: "AutoRotate",
+		28: "NDFilter",
+		29: "SelfTimer2",
+		33: "FlashOutput",
+	},
+	CanonFileInfoTagID: {
+		1: "FileNumber",
+		3: "BracketMode",
+		4: "BracketValue",
+		5: "BracketShotNumber",
+		6: "RawJpgQuality",
+		7: "RawJpgSize",
+		8: "LongExposureNoiseReduction",
+		9: "WBBracketMode",
+	},
+}
+
+func init() {
+	for arrayID, names := range canonArrays {
+		for index, name := range names {
+			tagNames[fmt.Sprintf("%04x/%04x/%04x/%04x", exifTagID, makerNotesTagID, arrayID, index)] = "Canon " + name
+		}
+	}
+	tagNames[fmt.Sprintf("%04x/%04x/%04x", exifTagID, makerNotesTagID, CanonLensModelTagID)] = "Canon Lens Model"
+	tagNames[fmt.Sprintf("%04x/%04x/%04x", exifTagID, makerNotesTagID, CanonSerialNumberTagID)] = "Canon Serial Number"
+	tagNames[fmt.Sprintf("%04x/%04x/%04x", exifTagID, makerNotesTagID, CanonInternalSerialNumberTagID)] = "Canon Internal Serial Number"
+}
+
+// Canon maker notes are IFD without a header. Offsets are relative to the main TIFF header
+func canonReader(file File, entry ifdEntry) (*ifd, error) {
+	if entry.ComponentCount < 2 {
+		return nil, nil
+	}
+	return readIfd(file, int64(entry.Data), entry.IfdIndex)
+}
+
+// decodeCanonMakerNote adds a tag for every known value of Canon arrays
+func decodeCanonMakerNote(file File, parentIDs []uint16, tags Tags, camera cameraIdentity) Tags {
+	result := make(Tags, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, tag)
+		names, ok := canonArrays[tag.ID]
+		if !ok {
+			continue
+		}
+		values, ok := tag.Value.([]uint16)
+		if !ok {
+			continue
+		}
+		for index := range names {
+			if int(index) < len(values) {
+				result = append(result, Tag{
+					ID:       index,
+					IDPath:   childPath(parentIDs, tag.ID),
+					DataType: TypeUnsignedShort,
+					Value:    []uint16{values[index]},
+				})
+			}
+		}
+	}
+	return result
+}
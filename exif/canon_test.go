@@ -0,0 +1,70 @@
+package exif
+
+import (
+	"testing"
+)
+
+func TestReadingCanonMakerNotes(t *testing.T) {
+	settings := make([]uint16, 47)
+	settings[7] = 1      // AI Servo AF
+	settings[22] = 61182 // RF lens
+	tiff := buildTiff([]tiffEntry{
+		asciiEntry(0x010f, "Canon"),
+		asciiEntry(0x0110, "Canon EOS R6"),
+		{ID: exifTagID, Sub: []tiffEntry{
+			{ID: makerNotesTagID, Type: TypeUndefined, Sub: []tiffEntry{
+				shortEntry(CanonCameraSettingsTagID, settings...),
+				longEntry(CanonSerialNumberTagID, 123456789),
+				asciiEntry(CanonLensModelTagID, "RF24-105mm F4 L IS USM"),
+			}},
+		}},
+	})
+	tagMap := readTestTags(t, writeTempFile(t, ".jpg", buildJpeg(tiff)))
+
+	if tag, ok := tagMap["8769/927c/0095"]; !ok || tag.Value.(string) != "RF24-105mm F4 L IS USM" {
+		t.Fatalf("Failed to read Canon lens model")
+	}
+	if tag, ok := tagMap["8769/927c/000c"]; !ok || tag.Value.([]uint32)[0] != 123456789 {
+		t.Fatalf("Failed to read Canon serial number")
+	}
+	tag, ok := tagMap["8769/927c/0001/0016"]
+	if !ok || tag.Value.([]uint16)[0] != 61182 {
+		t.Fatalf("Failed to read Canon lens type from camera settings")
+	}
+	if tag.Name() != "Canon LensType" {
+		t.Fatalf("Invalid name of lens type tag: %s", tag.Name())
+	}
+	if tag, ok := tagMap["8769/927c/0001/0007"]; !ok || tag.Value.([]uint16)[0] != 1 {
+		t.Fatalf("Failed to read Canon focus mode from camera settings")
+	}
+}
+
+func TestCanonMakerNotesRequireMake(t *testing.T) {
+	tiff := buildTiff([]tiffEntry{
+		asciiEntry(0x010f, "OtherMake"),
+		{ID: exifTagID, Sub: []tiffEntry{
+			{ID: makerNotesTagID, Type: TypeUndefined, Sub: []tiffEntry{
+				asciiEntry(CanonLensModelTagID, "RF24-105mm F4 L IS USM"),
+			}},
+		}},
+	})
+	tagMap := readTestTags(t, writeTempFile(t, ".jpg", buildJpeg(tiff)))
+	if _, ok := tagMap["8769/927c/0095"]; ok {
+		t.Fatalf("Maker notes of unknown camera must not be read")
+	}
+}
+
+func TestBrokenMakerNotesKeepExif(t *testing.T) {
+	// maker notes IFD claims 0x7fff entries which go far beyond the end of file
+	tiff := buildTiff([]tiffEntry{
+		asciiEntry(0x010f, "Canon"),
+		{ID: exifTagID, Sub: []tiffEntry{
+			shortEntry(0x8827, 400),
+			undefinedEntry(makerNotesTagID, []byte{0x7f, 0xff, 0, 0, 0, 0, 0, 0}),
+		}},
+	})
+	tagMap := readTestTags(t, writeTempFile(t, ".jpg", buildJpeg(tiff)))
+	if tag, ok := tagMap["8769/8827"]; !ok || tag.Value.([]uint16)[0] != 400 {
+		t.Fatalf("Exif must be read when maker notes are broken")
+	}
+}
@@ -0,0 +1,107 @@
+package exif
+
+import (
+	"fmt"
+)
+
+const (
+	// user type of Canon uuid box in "moov" box, which contains metadata boxes
+	canonUUID = "85c0b687820f11e08111f4ce462b6a48"
+)
+
+// cr3Block is a TIFF structure stored in one of the CMT boxes
+type cr3Block struct {
+	Box string
+	// path prefix of the tags in the block. Tags of CMT1 have no prefix, CMT2 contains Exif IFD and so on
+	Path []uint16
+}
+
+// CMT3 box contains Canon maker notes
+const cr3MakerNoteBlock = "CMT3"
+
+var cr3Blocks = []cr3Block{
+	{"CMT1", nil},
+	{"CMT2", []uint16{exifTagID}},
+	{cr3MakerNoteBlock, []uint16{exifTagID, makerNotesTagID}},
+	{"CMT4", []uint16{gpsTagID}},
+}
+
+// OpenCr3Blocks finds CMT boxes in Canon CR3 file and returns a map of box name to exif.File which reads TIFF
+// structure in the box
+func OpenCr3Blocks(file File) (map[string]File, error) {
+	topBoxes, err := readTopLevelBoxes(file)
+	if err != nil {
+		return nil, err
+	}
+	moov := findBox(topBoxes, "moov")
+	if moov == nil {
+		return nil, fmt.Errorf("No 'moov' box in CR3 file %s", file.GetPath())
+	}
+	moovBoxes, err := readChildBoxes(file, moov)
+	if err != nil {
+		return nil, err
+	}
+	canon := findUUIDBox(moovBoxes, canonUUID)
+	if canon == nil {
+		return nil, fmt.Errorf("No Canon metadata in CR3 file %s", file.GetPath())
+	}
+	return readCanonBlocks(file, canon)
+}
+
+// readCanonBlocks reads CMT boxes from Canon uuid box. Canon uses the same boxes in CR3 and in MP4 video files
+func readCanonBlocks(file File, canon *bmffBox) (map[string]File, error) {
+	canonBoxes, err := readChildBoxes(file, canon)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]File)
+	for _, block := range cr3Blocks {
+		box := findBox(canonBoxes, block.Box)
+		if box == nil {
+			continue
+		}
+		data, err := readBoxData(file, box)
+		if err != nil {
+			return nil, err
+		}
+		result[block.Box] = newExifFileBytes(file.GetPath(), data)
+	}
+	return result, nil
+}
+
+// readCr3Tags reads tags from all CMT boxes of Canon CR3 file
+func readCr3Tags(file File) (Tags, error) {
+	blockFiles, err := OpenCr3Blocks(file)
+	if err != nil {
+		return nil, err
+	}
+	return canonBlocksToTags(blockFiles)
+}
+
+func canonBlocksToTags(blockFiles map[string]File) (Tags, error) {
+	tags := make(Tags, 0)
+	for _, block := range cr3Blocks {
+		blockFile, ok := blockFiles[block.Box]
+		if !ok {
+			continue
+		}
+		ifds, err := readTiffIfds(blockFile)
+		if err != nil {
+			return nil, err
+		}
+		var blockTags Tags
+		if block.Path == nil {
+			blockTags, err = ifdsToTags(blockFile, ifds)
+		} else {
+			blockTags, err = entriesToTags(block.Path, blockFile, ifds[0].IfdEntries, cameraIdentity{})
+		}
+		if err != nil {
+			return nil, err
+		}
+		if block.Box == cr3MakerNoteBlock {
+			blockTags = decodeCanonMakerNote(blockFile, block.Path, blockTags, cameraIdentity{})
+		}
+		tags = append(tags, blockTags...)
+	}
+	return tags, nil
+}
@@ -0,0 +1,109 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+)
+
+// imageFileBytes is an in-memory exif file. It is used to read TIFF structures which are stored in blocks inside
+// container files, i.e. CMT boxes in Canon CR3 files
+type imageFileBytes struct {
+	Path             string
+	Order            byte
+	Reader           *bytes.Reader
+	TiffHeaderOffset int64
+}
+
+// newExifFileBytes creates exif file from a block of data. Path is the path of the file that contains the data
+func newExifFileBytes(path string, data []byte) File {
+	return &imageFileBytes{
+		Path:   path,
+		Reader: bytes.NewReader(data),
+		Order:  BigEndian,
+	}
+}
+
+func (file imageFileBytes) readUint16() (uint16, error) {
+	var word uint16
+	err := binary.Read(file.Reader, file.getByteOrder(), &word)
+	if err != nil {
+		return 0, err
+	}
+	return word, nil
+}
+
+func (file imageFileBytes) readUint32() (uint32, error) {
+	var word uint32
+	err := binary.Read(file.Reader, file.getByteOrder(), &word)
+	if err != nil {
+		return 0, err
+	}
+	return word, nil
+}
+
+func (file imageFileBytes) readBytes(size uint16) ([]byte, error) {
+	buf := make([]byte, size)
+	_, err := file.Reader.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (file imageFileBytes) currentPosition() (int64, error) {
+	return file.Reader.Seek(0, io.SeekCurrent)
+}
+
+func (file imageFileBytes) seek(pos int64) (int64, error) {
+	return file.Reader.Seek(pos, io.SeekStart)
+}
+
+func (file imageFileBytes) seekRelative(pos int64) (int64, error) {
+	return file.Reader.Seek(pos, io.SeekCurrent)
+}
+
+func (file imageFileBytes) size() (int64, error) {
+	return file.Reader.Size(), nil
+}
+
+func (file imageFileBytes) Read(out interface{}) error {
+	return binary.Read(file.Reader, file.getByteOrder(), out)
+}
+
+// Close does nothing, there is no underlying file
+func (file imageFileBytes) Close() {
+}
+
+func (file imageFileBytes) GetFile() *os.File {
+	return nil
+}
+
+func (file imageFileBytes) getByteOrder() binary.ByteOrder {
+	if file.Order == BigEndian {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+
+}
+
+func (file *imageFileBytes) SetOrder(newOrder byte) {
+	file.Order = newOrder
+}
+
+func (file imageFileBytes) GetOrder() byte {
+	return file.Order
+}
+
+func (file imageFileBytes) GetTiffHeaderOffset() int64 {
+	return file.TiffHeaderOffset
+}
+
+func (file imageFileBytes) GetPath() string {
+	return file.Path
+}
+
+func (file *imageFileBytes) SetTiffHeaderOffset(newOffset int64) {
+	file.TiffHeaderOffset = newOffset
+}
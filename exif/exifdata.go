@@ -35,6 +35,8 @@ type ifd struct {
 	Index      int
 	EntryCount uint16
 	IfdEntries []ifdEntry
+	// SubIfds are nested IFDs of maker notes keyed by ID of the entry pointing to them
+	SubIfds map[uint16]*ifd
 }
 
 // ToString returns a string representation of IfdEntry
@@ -62,6 +64,11 @@ func (tag Tag) PathName() string {
 	return sb.String()
 }
 
+// Name returns human-readable name of the tag, or empty string for the tags without a known name
+func (tag Tag) Name() string {
+	return tagNames[tag.PathName()]
+}
+
 // ToString returns a string representation of IfdEntry
 func (tag Tag) ToString() string {
 	return fmt.Sprintf("Path=%s ID=%x Value=%v", tag.PathName(), tag.ID, tag.Value)
@@ -159,6 +166,23 @@ func NewRational(numerator uint32, denominator uint32) Rational {
 		Denominator: denominator}
 }
 
+// ParseRational parses rational value written as "numerator/denominator" or as a whole number, as used in XMP
+func ParseRational(value string) (Rational, error) {
+	parts := strings.SplitN(strings.TrimSpace(value), "/", 2)
+	numerator, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return Rational{}, fmt.Errorf("Invalid rational value %s", value)
+	}
+	denominator := uint64(1)
+	if len(parts) > 1 {
+		denominator, err = strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			return Rational{}, fmt.Errorf("Invalid rational value %s", value)
+		}
+	}
+	return NewRational(uint32(numerator), uint32(denominator)), nil
+}
+
 // SignedRational represents a rational value expressed as Numerator/Denominator
 type SignedRational struct {
 	Numerator   int32
@@ -20,6 +20,7 @@ type File interface {
 	currentPosition() (int64, error)
 	seek(pos int64) (int64, error)
 	seekRelative(int64) (int64, error)
+	size() (int64, error)
 	Read(interface{}) error
 	Close()
 	GetPath() string
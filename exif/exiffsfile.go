@@ -38,9 +38,9 @@ func OpenExifFileIo(filepath string) (file File, err error) {
 		File:  f,
 		Order: BigEndian,
 	}
-	word, err := file.readUint16()
-	if word != 0xFFD8 {
-		return nil, fmt.Errorf("Not a JPEG file? %s", filepath)
+	_, err = detectFormat(file)
+	if err != nil {
+		return nil, err
 	}
 
 	return file, nil
@@ -85,6 +85,14 @@ func (file imageFileFs) seekRelative(pos int64) (int64, error) {
 	return file.File.Seek(pos, os.SEEK_CUR)
 }
 
+func (file imageFileFs) size() (int64, error) {
+	info, err := file.File.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
 func (file imageFileFs) Read(out interface{}) error {
 	return binary.Read(file.File, file.getByteOrder(), out)
 }
@@ -50,9 +50,9 @@ func OpenExifFileMMap(filepath string) (file File, err error) {
 		Reader: reader,
 		Order:  BigEndian,
 	}
-	word, err := file.readUint16()
-	if word != 0xFFD8 {
-		return nil, fmt.Errorf("Not a JPEG file? %s", filepath)
+	_, err = detectFormat(file)
+	if err != nil {
+		return nil, err
 	}
 
 	return file, nil
@@ -97,6 +97,10 @@ func (file imageFileMmap) seekRelative(pos int64) (int64, error) {
 	return file.Reader.Seek(pos, io.SeekCurrent)
 }
 
+func (file imageFileMmap) size() (int64, error) {
+	return int64(len(file.Data)), nil
+}
+
 func (file imageFileMmap) Read(out interface{}) error {
 	return binary.Read(file.Reader, file.getByteOrder(), out)
 }
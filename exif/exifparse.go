@@ -25,16 +25,42 @@ var (
 	nikonIso             = "8769/927c/0002"
 	lensMake             = "8769/a433"
 	lensModel            = "8769/a434"
+	colorSpace           = "8769/a001"
+	interopIndex         = "8769/a005/0001"
+	gpsLatitudeRef       = "8825/0001"
+	gpsLatitude          = "8825/0002"
+	gpsLongitudeRef      = "8825/0003"
+	gpsLongitude         = "8825/0004"
+	gpsAltitudeRef       = "8825/0005"
+	gpsAltitude          = "8825/0006"
+	gpsTimeStamp         = "8825/0007"
+	gpsImgDirection      = "8825/0011"
+	gpsDateStamp         = "8825/001d"
+	thumbnailOffset      = "0001/0201"
+	thumbnailLength      = "0001/0202"
 )
 
 const (
 	// ExifDataMarker is an identifier of Exif Data marker
+	soiDataMarker  = 0xFFD8 // start of image marker
 	exifDataMarker = 0xFFE1
+	app2Marker     = 0xFFE2 // ICC profile and Multi-Picture Format
+	app13Marker    = 0xFFED // Photoshop image resources
 	eoiDataMarker  = 0xFFD9
 	sosDataMarker  = 0xFFDA // star of stream marker
 
+	exifIdentifier = "Exif"
+	xmpIdentifier  = "http://ns.adobe.com/xap/1.0/"
+	// APPn segments identifiers are short zero-terminated strings
+	maxSegmentIdentifierSize = 64
+
+	makeTagID       = 0x010f
+	modelTagID      = 0x0110
+	subIfdsTagID    = 0x014a
 	exifTagID       = 0x8769
+	gpsTagID        = 0x8825
 	makerNotesTagID = 0x927c
+	interopTagID    = 0xa005
 
 	// TypeUnknown is an unknown Tag type
 	TypeUnknown = 0
@@ -62,6 +88,8 @@ const (
 	TypeSingleFloat = 11
 	// TypeDoubleFloat is a float64
 	TypeDoubleFloat = 12
+	// TypeIfd is an uint32 offset to IFD
+	TypeIfd = 13
 )
 
 // limited set of known tag names that is used in exif-stat
@@ -83,6 +111,19 @@ var tagNames = map[string]string{
 	nikonIso:             "ISO",
 	lensMake:             "Lens Make",
 	lensModel:            "Lens Model",
+	colorSpace:           "Color Space",
+	interopIndex:         "Interoperability Index",
+	gpsLatitudeRef:       "GPS Latitude Ref",
+	gpsLatitude:          "GPS Latitude",
+	gpsLongitudeRef:      "GPS Longitude Ref",
+	gpsLongitude:         "GPS Longitude",
+	gpsAltitudeRef:       "GPS Altitude Ref",
+	gpsAltitude:          "GPS Altitude",
+	gpsTimeStamp:         "GPS Time Stamp",
+	gpsImgDirection:      "GPS Image Direction",
+	gpsDateStamp:         "GPS Date Stamp",
+	thumbnailOffset:      "Thumbnail Offset",
+	thumbnailLength:      "Thumbnail Length",
 }
 
 type tagReader func(file File, count uint32) (interface{}, []byte, error)
@@ -100,7 +141,8 @@ var (
 		signedLongReader,       // signed long
 		signedRationalReader,   // signed rational
 		float32Reader,          // single float
-		float64Reader}          // double float
+		float64Reader,          // double float
+		unsignedLongReader}     // IFD offset
 )
 
 func readRawData(file File, count uint32, bytesInElement uint32) ([]byte, error) {
@@ -233,8 +275,8 @@ func unsignedRationalReader(file File, count uint32) (interface{}, []byte, error
 		return nil, nil, err
 	}
 	rationals := make([]Rational, count)
-	for index := uint32(0); index < count; index += 2 {
-		rationals[index/2] = NewRational(longs[index], longs[index+1])
+	for index := uint32(0); index < count; index++ {
+		rationals[index] = NewRational(longs[index*2], longs[index*2+1])
 	}
 	return rationals, rawData, nil
 }
@@ -250,8 +292,8 @@ func signedRationalReader(file File, count uint32) (interface{}, []byte, error)
 		return nil, nil, err
 	}
 	rationals := make([]SignedRational, count)
-	for index := uint32(0); index < count; index += 2 {
-		rationals[index/2] = NewSignedRational(longs[index], longs[index+1])
+	for index := uint32(0); index < count; index++ {
+		rationals[index] = NewSignedRational(longs[index*2], longs[index*2+1])
 	}
 	return rationals, rawData, nil
 }
@@ -314,7 +356,7 @@ func readIfdEntry(file File) (*ifdEntry, error) {
 	if err != nil {
 		return nil, err
 	}
-	if dataFormat < 1 || dataFormat > 12 {
+	if dataFormat < 1 || int(dataFormat) > len(dataFormatTypes) {
 		return nil, fmt.Errorf("Unsupported data type format: %d of Tag ID %0x", dataFormat, tagNumber)
 	}
 
@@ -382,16 +424,25 @@ func entryToTag(parents []uint16, entry ifdEntry) Tag {
 	}
 }
 
-func entriesToTags(parentIDs []uint16, file File, entries []ifdEntry) (Tags, error) {
+// childPath creates a new tag path by appending ids to the parent path. Parent path is never modified
+func childPath(parentIDs []uint16, ids ...uint16) []uint16 {
+	path := make([]uint16, 0, len(parentIDs)+len(ids))
+	path = append(path, parentIDs...)
+	return append(path, ids...)
+}
+
+// entriesToTags converts IFD entries into tags, following the pointers to the other IFDs. Camera make and model are
+// required to recognize maker notes which do not have a header and to decode model-specific maker notes
+func entriesToTags(parentIDs []uint16, file File, entries []ifdEntry, camera cameraIdentity) (Tags, error) {
 	tags := make([]Tag, 0)
 	for _, entry := range entries {
-		if entry.TagID == exifTagID {
+		if entry.TagID == exifTagID || entry.TagID == gpsTagID || (entry.TagID == interopTagID && isIfdOffsetList(entry)) {
 			exifTagEntries, err := readIfd(file, int64(entry.Value.([]uint32)[0]), entry.IfdIndex)
 			if err != nil {
 				return nil, err
 			}
-			parents := append(parentIDs, entry.TagID)
-			exifTags, err := entriesToTags(parents, file, exifTagEntries.IfdEntries)
+			parents := childPath(parentIDs, entry.TagID)
+			exifTags, err := entriesToTags(parents, file, exifTagEntries.IfdEntries, camera)
 			if err != nil {
 				return nil, err
 			}
@@ -399,19 +450,24 @@ func entriesToTags(parentIDs []uint16, file File, entries []ifdEntry) (Tags, err
 				tags = append(tags, tag)
 			}
 		} else if entry.TagID == makerNotesTagID {
-			exifTagEntries, err := readMakerNotes(file, entry)
+			makerNoteTags, err := readMakerNoteTags(childPath(parentIDs, entry.TagID), file, entry, camera)
 			if err != nil {
 				return nil, err
 			}
-			if exifTagEntries != nil {
-				parents := append(parentIDs, entry.TagID)
-				exifTags, err := entriesToTags(parents, file, exifTagEntries.IfdEntries)
+			tags = append(tags, makerNoteTags...)
+		} else if entry.TagID == subIfdsTagID && isIfdOffsetList(entry) {
+			// SubIFDs in RAW files usually contain full-size image data and previews. Each of them gets its own index
+			// in the path, i.e. width of the first sub-IFD has path "014a/0000/0100"
+			for index, offset := range entry.Value.([]uint32) {
+				subIfd, err := readIfd(file, int64(offset), entry.IfdIndex)
 				if err != nil {
 					return nil, err
 				}
-				for _, tag := range exifTags {
-					tags = append(tags, tag)
+				subIfdTags, err := entriesToTags(childPath(parentIDs, entry.TagID, uint16(index)), file, subIfd.IfdEntries, camera)
+				if err != nil {
+					return nil, err
 				}
+				tags = append(tags, subIfdTags...)
 			}
 		} else {
 			tag := entryToTag(parentIDs, entry)
@@ -421,6 +477,11 @@ func entriesToTags(parentIDs []uint16, file File, entries []ifdEntry) (Tags, err
 	return tags, nil
 }
 
+func isIfdOffsetList(entry ifdEntry) bool {
+	_, ok := entry.Value.([]uint32)
+	return ok
+}
+
 func readExifHeader(file File, marker *marker) error {
 	// check headers
 	file.seek(marker.Offset)
@@ -481,21 +542,37 @@ func readIfds(file File) ([]ifd, error) {
 	}
 }
 
+// tiffMagics lists the values allowed after the byte order mark in the TIFF header. Some RAW formats use their own
+// values instead of the standard 42
+var tiffMagics = map[uint16]bool{
+	0x002A: true, // standard TIFF
+	0x4F52: true, // Olympus ORF, "IIRO"/"MMOR"
+	0x5352: true, // Olympus ORF, "IIRS"
+	0x0055: true, // Panasonic RW2, "IIU"
+}
+
 func readTiffHeader(file File) error {
 	// examine TIFF header
 
-	var wword uint32
-	err := file.Read(&wword)
+	byteOrder, err := file.readBytes(2)
 	if err != nil {
 		return err
 	}
-	if wword == 0x49492A00 {
+	if byteOrder[0] == LittleEndian && byteOrder[1] == LittleEndian {
 		file.SetOrder(LittleEndian)
-	} else if wword == 0x4d4d002A {
+	} else if byteOrder[0] == BigEndian && byteOrder[1] == BigEndian {
 		file.SetOrder(BigEndian)
 	} else {
-		return fmt.Errorf("Invalid byte order in TIFF header %x", wword)
+		return fmt.Errorf("Invalid byte order in TIFF header %x", byteOrder)
+	}
+	magic, err := file.readUint16()
+	if err != nil {
+		return err
+	}
+	if !tiffMagics[magic] {
+		return fmt.Errorf("Invalid byte order in TIFF header %x%04x", byteOrder, magic)
 	}
+	var wword uint32
 	err = file.Read(&wword)
 	if err != nil {
 		return err
@@ -507,40 +584,264 @@ func readTiffHeader(file File) error {
 	return nil
 }
 
-// ReadExifTags parses file, extracts Ifds from it and parses ifds for all tags
-func ReadExifTags(file File) (Tags, error) {
-	// find exif marker in the file
-	var marker *marker
-	var err error
+func jpegDetector(header []byte) bool {
+	return binary.BigEndian.Uint16(header) == soiDataMarker
+}
+
+// readJpegMetadata scans JPEG markers and reads Exif data and other supported metadata segments
+func readJpegMetadata(file File) (*Metadata, error) {
+	return readJpegMetadataAt(file, 0)
+}
+
+// readJpegTagsAt reads tags from JPEG image starting at the given offset in the file. Used to read JPEG images
+// embedded in RAW files
+func readJpegTagsAt(file File, offset int64) (Tags, error) {
+	metadata, err := readJpegMetadataAt(file, offset)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.Tags, nil
+}
+
+// jpegSegmentReader reads data of the JPEG segment into metadata. Segment data starts at the marker offset
+type jpegSegmentReader func(file File, marker *marker, metadata *Metadata) error
+
+var jpegSegmentReaders = map[uint16]jpegSegmentReader{
+	sof0Marker:     readSofSegment,
+	sof1Marker:     readSofSegment,
+	sof2Marker:     readSofSegment,
+	dqtMarker:      readDqtSegment,
+	exifDataMarker: readApp1Segment,
+	app2Marker:     readApp2Segment,
+	app13Marker:    readApp13Segment,
+}
+
+func readJpegMetadataAt(file File, offset int64) (*Metadata, error) {
+	file.SetOrder(BigEndian)        // JPEG markers are always big endian
+	_, err := file.seek(offset + 2) // skip SOI marker
+	if err != nil {
+		return nil, err
+	}
+	metadata := &Metadata{}
 	for {
-		marker, err = readMarker(file)
+		marker, err := readMarker(file)
 		if err != nil {
 			return nil, err
 		}
-		if marker.Marker == exifDataMarker {
+		if marker.Marker == sosDataMarker || marker.Marker == eoiDataMarker {
+			// no more metadata after start of stream
 			break
-		} else if marker.Marker == sosDataMarker {
-			// file does not contain proper exif
-			return make(Tags, 0), nil
-		} else {
-			file.seekRelative(int64(marker.Size - 2))
 		}
+		if reader, ok := jpegSegmentReaders[marker.Marker]; ok {
+			err = reader(file, marker, metadata)
+			if err != nil {
+				return nil, err
+			}
+			file.SetOrder(BigEndian) // segment readers may change byte order
+		}
+		_, err = file.seek(marker.Offset + int64(marker.Size) - 2)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if metadata.Tags == nil {
+		// file does not contain proper exif
+		metadata.Tags = make(Tags, 0)
+	}
+	if len(metadata.Images) > 0 {
+		metadata.Images[0].Offset = offset
+	}
+	return metadata, nil
+}
+
+// readSegmentIdentifier reads zero-terminated identifier at the start of APPn segment
+func readSegmentIdentifier(file File, marker *marker) (string, error) {
+	if marker.Size < 2 {
+		return "", fmt.Errorf("Invalid size of segment %04x: %d", marker.Marker, marker.Size)
+	}
+	_, err := file.seek(marker.Offset)
+	if err != nil {
+		return "", err
 	}
-	if marker.Marker != exifDataMarker {
-		return nil, fmt.Errorf("Cannot find exif data in %s", file.GetPath())
+	size := marker.Size - 2
+	if size > maxSegmentIdentifierSize {
+		size = maxSegmentIdentifierSize
 	}
-	err = readExifHeader(file, marker)
+	data, err := file.readBytes(size)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
+	if end := bytes.IndexByte(data, 0); end >= 0 {
+		return string(data[:end]), nil
+	}
+	return string(data), nil
+}
 
-	ifds, err := readIfds(file)
+// readSegmentData reads data of APPn segment which follows zero-terminated identifier
+func readSegmentData(file File, marker *marker, identifier string) ([]byte, error) {
+	size := int(marker.Size) - 2 - len(identifier) - 1
+	if size < 0 {
+		return nil, fmt.Errorf("Segment %s is too short: %d", identifier, marker.Size)
+	}
+	_, err := file.seek(marker.Offset + int64(len(identifier)) + 1)
 	if err != nil {
 		return nil, err
 	}
+	data := make([]byte, size)
+	err = file.Read(data)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readApp1Segment classifies APP1 segments by their identifier. APP1 can contain either Exif or XMP data
+func readApp1Segment(file File, marker *marker, metadata *Metadata) error {
+	identifier, err := readSegmentIdentifier(file, marker)
+	if err != nil {
+		return err
+	}
+	switch identifier {
+	case exifIdentifier:
+		if metadata.Tags != nil { // only the first Exif segment is read
+			return nil
+		}
+		err = readExifHeader(file, marker)
+		if err != nil {
+			return err
+		}
+		ifds, err := readIfds(file)
+		if err != nil {
+			return err
+		}
+		metadata.Tags, err = ifdsToTags(file, ifds)
+		return err
+	case xmpIdentifier:
+		return readXmpSegment(file, marker, identifier, metadata)
+	}
+	return nil
+}
+
+// readApp2Segment classifies APP2 segments by their identifier. APP2 can contain either MPF index or ICC profile
+func readApp2Segment(file File, marker *marker, metadata *Metadata) error {
+	identifier, err := readSegmentIdentifier(file, marker)
+	if err != nil {
+		return err
+	}
+	switch identifier {
+	case mpfIdentifier:
+		if metadata.Images == nil {
+			return readMpfSegment(file, marker, metadata)
+		}
+	case iccIdentifier:
+		return readIccSegment(file, marker, metadata)
+	}
+	return nil
+}
+
+// cameraIdentity is camera make and model from IFD0
+type cameraIdentity struct {
+	Make  string
+	Model string
+}
+
+// entriesCamera returns camera make and model from IFD0 entries
+func entriesCamera(entries []ifdEntry) cameraIdentity {
+	camera := cameraIdentity{}
+	for _, entry := range entries {
+		value, ok := entry.Value.(string)
+		if !ok {
+			continue
+		}
+		switch entry.TagID {
+		case makeTagID:
+			camera.Make = strings.TrimSpace(value)
+		case modelTagID:
+			camera.Model = strings.TrimSpace(value)
+		}
+	}
+	return camera
+}
+
+// ifdsToTags converts all IFDs into tags. Tags from IFD0 have no path prefix, tags from the following IFDs are
+// prefixed with the IFD index, i.e. thumbnail offset in IFD1 has path "0001/0201"
+func ifdsToTags(file File, ifds []ifd) (Tags, error) {
+	tags := make(Tags, 0)
+	camera := cameraIdentity{}
+	if len(ifds) > 0 {
+		camera = entriesCamera(ifds[0].IfdEntries)
+	}
+	for _, ifd := range ifds {
+		parent := make([]uint16, 0)
+		if ifd.Index > 0 {
+			parent = append(parent, uint16(ifd.Index))
+		}
+		ifdTags, err := entriesToTags(parent, file, ifd.IfdEntries, camera)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, ifdTags...)
+	}
+	return tags, nil
+}
+
+func tagUint32(tag Tag) (uint32, bool) {
+	switch value := tag.Value.(type) {
+	case []uint32:
+		if len(value) > 0 {
+			return value[0], true
+		}
+	case []uint16:
+		if len(value) > 0 {
+			return uint32(value[0]), true
+		}
+	}
+	return 0, false
+}
 
-	parent := make([]uint16, 0)
-	return entriesToTags(parent, file, ifds[0].IfdEntries) // we're ignoring any IFD except for IFD0 for now
+// ReadThumbnail reads Exif tags from the file and returns the embedded JPEG thumbnail referenced from IFD1.
+// Returns nil if there is no thumbnail in the file
+func ReadThumbnail(file File) ([]byte, error) {
+	tags, err := ReadExifTags(file)
+	if err != nil {
+		return nil, err
+	}
+	tagMap := TagsAsMap(tags)
+	offsetTag, ok := tagMap[thumbnailOffset]
+	if !ok {
+		return nil, nil
+	}
+	lengthTag, ok := tagMap[thumbnailLength]
+	if !ok {
+		return nil, nil
+	}
+	offset, ok := tagUint32(offsetTag)
+	if !ok {
+		return nil, fmt.Errorf("Invalid thumbnail offset in %s", file.GetPath())
+	}
+	length, ok := tagUint32(lengthTag)
+	if !ok || length == 0 {
+		return nil, fmt.Errorf("Invalid thumbnail length in %s", file.GetPath())
+	}
+	// broken offset or length must not make us allocate more than the file holds
+	fileSize, err := file.size()
+	if err != nil {
+		return nil, err
+	}
+	start := file.GetTiffHeaderOffset() + int64(offset)
+	if start+int64(length) > fileSize {
+		return nil, fmt.Errorf("Thumbnail is out of bounds in %s", file.GetPath())
+	}
+	_, err = file.seek(start)
+	if err != nil {
+		return nil, err
+	}
+	thumbnail := make([]byte, length)
+	err = file.Read(thumbnail)
+	if err != nil {
+		return nil, err
+	}
+	return thumbnail, nil
 }
 
 // TagsAsMap converts list of tags into a map of tag path -> tag
@@ -0,0 +1,84 @@
+package exif
+
+import (
+	"testing"
+)
+
+func TestReadingGpsIfd(t *testing.T) {
+	tiff := buildTiff([]tiffEntry{
+		asciiEntry(0x010f, "TestMake"),
+		{ID: gpsTagID, Sub: []tiffEntry{
+			asciiEntry(0x0001, "S"),
+			rationalEntry(0x0002, 33, 1, 51, 1, 5400, 100),
+			asciiEntry(0x0003, "E"),
+			rationalEntry(0x0004, 151, 1, 12, 1, 3000, 100),
+			byteEntry(0x0005, 0),
+			rationalEntry(0x0006, 125, 10),
+		}},
+	})
+	tagMap := readTestTags(t, writeTempFile(t, ".jpg", buildJpeg(tiff)))
+
+	tag, ok := tagMap[gpsLatitude]
+	if !ok {
+		t.Fatalf("Failed to find GPS latitude tag")
+	}
+	dms := tag.Value.([]Rational)
+	if len(dms) != 3 || dms[0].AsFloat() != 33 || dms[1].AsFloat() != 51 || dms[2].AsFloat() != 54 {
+		t.Fatalf("Invalid GPS latitude, expected: [33 51 54], actual: %v", dms)
+	}
+	if tag, ok := tagMap[gpsLatitudeRef]; !ok || tag.Value.(string) != "S" {
+		t.Fatalf("Invalid GPS latitude reference: %v", tag.Value)
+	}
+	if tag, ok := tagMap[gpsAltitude]; !ok || tag.Value.([]Rational)[0].AsFloat() != 12.5 {
+		t.Fatalf("Invalid GPS altitude: %v", tag.Value)
+	}
+	if _, ok := tagMap["8825"]; ok {
+		t.Fatalf("GPS IFD pointer must not be reported as a tag")
+	}
+}
+
+func TestReadingThumbnail(t *testing.T) {
+	thumbnail := []byte{0xFF, 0xD8, 0xFF, 0xD9}
+	ifd0 := []tiffEntry{asciiEntry(0x010f, "TestMake")}
+	// thumbnail goes right after the TIFF structure, size of which does not depend on the offset value
+	offset := uint32(len(buildTiff(ifd0, []tiffEntry{longEntry(0x0201, 0), longEntry(0x0202, 0)})))
+	tiff := buildTiff(ifd0, []tiffEntry{longEntry(0x0201, offset), longEntry(0x0202, uint32(len(thumbnail)))})
+	tiff = append(tiff, thumbnail...)
+
+	file, err := OpenExifFileIo(writeTempFile(t, ".jpg", buildJpeg(tiff)))
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+
+	data, err := ReadThumbnail(file)
+	if err != nil {
+		t.Fatalf("Failed to read thumbnail: %v", err)
+	}
+	if string(data) != string(thumbnail) {
+		t.Fatalf("Invalid thumbnail, expected: %v, actual: %v", thumbnail, data)
+	}
+
+	tagMap := readTestTags(t, file.GetPath())
+	if tag, ok := tagMap[cameraMake]; !ok || tag.Value.(string) != "TestMake" {
+		t.Fatalf("IFD0 tags must not have path prefix")
+	}
+	if _, ok := tagMap[thumbnailLength]; !ok {
+		t.Fatalf("Failed to find thumbnail length in IFD1")
+	}
+}
+
+func TestReadingThumbnailOutOfBounds(t *testing.T) {
+	ifd0 := []tiffEntry{asciiEntry(0x010f, "TestMake")}
+	tiff := buildTiff(ifd0, []tiffEntry{longEntry(0x0201, 8), longEntry(0x0202, 0xfffffff0)})
+
+	file, err := OpenExifFileIo(writeTempFile(t, ".jpg", buildJpeg(tiff)))
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+
+	if _, err := ReadThumbnail(file); err == nil {
+		t.Fatalf("Thumbnail out of bounds must be reported")
+	}
+}
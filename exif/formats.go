@@ -0,0 +1,90 @@
+package exif
+
+import (
+	"fmt"
+)
+
+// number of bytes at the start of the file that are enough to detect the file format
+const formatHeaderSize = 16
+
+// FormatVideo is the format of MP4 and QuickTime video files
+const FormatVideo = "Video"
+
+// imageFormat is a container format which can store Exif data. Format is detected by the header of the file,
+// and then ReadTags finds Exif data in the container and parses it
+type imageFormat struct {
+	Name         string
+	CanRead      func([]byte) bool
+	ReadMetadata func(File) (*Metadata, error)
+}
+
+var imageFormats = []imageFormat{
+	{"JPEG", jpegDetector, readJpegMetadata},
+	{"RW2", rw2Detector, tagsOnly(readRw2Tags)},
+	{"RAF", rafDetector, tagsOnly(readRafTags)},
+	{"CR3", bmffDetector("crx "), tagsOnly(readCr3Tags)},
+	{"PNG", pngDetector, tagsOnly(readPngTags)},
+	{"WebP", webpDetector, tagsOnly(readWebpTags)},
+	{"JPEG XL", jxlDetector, tagsOnly(readJxlTags)},
+	{"HEIF", bmffDetector("heic", "heix", "heim", "heis", "mif1", "msf1", "avif", "avis"), tagsOnly(readHeifTags)},
+	// must follow the other ISO BMFF formats, as it accepts any ISO BMFF file
+	{FormatVideo, videoDetector, tagsOnly(readVideoTags)},
+	{"TIFF", tiffDetector, tagsOnly(readTiffTags)},
+}
+
+// tagsOnly adapts reader of the formats which only contain Exif tags to return metadata
+func tagsOnly(readTags func(File) (Tags, error)) func(File) (*Metadata, error) {
+	return func(file File) (*Metadata, error) {
+		tags, err := readTags(file)
+		if err != nil {
+			return nil, err
+		}
+		return &Metadata{Tags: tags}, nil
+	}
+}
+
+// detectFormat reads the start of the file and finds the format that can read it. File is positioned at the start
+// after detection
+func detectFormat(file File) (*imageFormat, error) {
+	_, err := file.seek(0)
+	if err != nil {
+		return nil, err
+	}
+	header, err := file.readBytes(formatHeaderSize)
+	if err != nil {
+		return nil, err
+	}
+	_, err = file.seek(0)
+	if err != nil {
+		return nil, err
+	}
+	for _, format := range imageFormats {
+		if format.CanRead(header) {
+			return &format, nil
+		}
+	}
+	return nil, fmt.Errorf("Unsupported file format %s", file.GetPath())
+}
+
+// ReadMetadata parses file and reads Exif tags and all the other supported metadata from it
+func ReadMetadata(file File) (*Metadata, error) {
+	format, err := detectFormat(file)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := format.ReadMetadata(file)
+	if err != nil {
+		return nil, err
+	}
+	metadata.Format = format.Name
+	return metadata, nil
+}
+
+// ReadExifTags parses file, extracts Ifds from it and parses ifds for all tags
+func ReadExifTags(file File) (Tags, error) {
+	metadata, err := ReadMetadata(file)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.Tags, nil
+}
@@ -0,0 +1,111 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func buildPngChunk(chunkType string, data []byte) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+	buf.WriteString(chunkType)
+	buf.Write(data)
+	buf.Write([]byte{0, 0, 0, 0}) // CRC is not checked
+	return buf.Bytes()
+}
+
+func buildRiffChunk(chunkType string, data []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(chunkType)
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+	if len(data)%2 == 1 {
+		buf.WriteByte(0)
+	}
+	return buf.Bytes()
+}
+
+func TestReadingPng(t *testing.T) {
+	tiff := buildTiff([]tiffEntry{asciiEntry(0x0110, "PNG Camera")})
+	png := bytes.Join([][]byte{
+		pngSignature,
+		buildPngChunk("IHDR", make([]byte, 13)),
+		buildPngChunk("eXIf", tiff),
+		buildPngChunk("IDAT", make([]byte, 10)),
+		buildPngChunk("IEND", nil),
+	}, nil)
+
+	tagMap := readTestTags(t, writeTempFile(t, ".png", png))
+	if tag, ok := tagMap[model]; !ok || tag.Value.(string) != "PNG Camera" {
+		t.Fatalf("Failed to read model from PNG file: %v", tagMap)
+	}
+}
+
+func TestReadingPngWithoutExif(t *testing.T) {
+	png := bytes.Join([][]byte{
+		pngSignature,
+		buildPngChunk("IHDR", make([]byte, 13)),
+		buildPngChunk("IDAT", make([]byte, 10)),
+		buildPngChunk("IEND", nil),
+	}, nil)
+
+	tagMap := readTestTags(t, writeTempFile(t, ".png", png))
+	if len(tagMap) != 0 {
+		t.Fatalf("Should have found no tags")
+	}
+}
+
+func TestReadingWebp(t *testing.T) {
+	tiff := buildTiff([]tiffEntry{asciiEntry(0x0110, "WebP Camera")})
+	chunks := bytes.Join([][]byte{
+		buildRiffChunk("VP8X", make([]byte, 10)),
+		buildRiffChunk("VP8 ", make([]byte, 11)),
+		buildRiffChunk("EXIF", append([]byte{'E', 'x', 'i', 'f', 0, 0}, tiff...)),
+	}, nil)
+	var webp bytes.Buffer
+	webp.WriteString("RIFF")
+	binary.Write(&webp, binary.LittleEndian, uint32(len(chunks)+4))
+	webp.WriteString("WEBP")
+	webp.Write(chunks)
+
+	tagMap := readTestTags(t, writeTempFile(t, ".webp", webp.Bytes()))
+	if tag, ok := tagMap[model]; !ok || tag.Value.(string) != "WebP Camera" {
+		t.Fatalf("Failed to read model from WebP file: %v", tagMap)
+	}
+}
+
+func TestReadingJxl(t *testing.T) {
+	tiff := buildTiff([]tiffEntry{asciiEntry(0x0110, "JXL Camera")})
+	jxl := bytes.Join([][]byte{
+		jxlSignature,
+		buildFtyp("jxl "),
+		buildBox("Exif", []byte{0, 0, 0, 0}, tiff),
+		buildBox("jxlc", []byte{0xFF, 0x0A}),
+	}, nil)
+
+	tagMap := readTestTags(t, writeTempFile(t, ".jxl", jxl))
+	if tag, ok := tagMap[model]; !ok || tag.Value.(string) != "JXL Camera" {
+		t.Fatalf("Failed to read model from JPEG XL file: %v", tagMap)
+	}
+}
+
+func TestReadingCompressedJxl(t *testing.T) {
+	jxl := bytes.Join([][]byte{
+		jxlSignature,
+		buildFtyp("jxl "),
+		buildBox("brob", []byte("Exif"), []byte{1, 2, 3, 4}),
+		buildBox("jxlc", []byte{0xFF, 0x0A}),
+	}, nil)
+
+	file, err := OpenExifFileIo(writeTempFile(t, ".jxl", jxl))
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+	_, err = ReadExifTags(file)
+	if !errors.Is(err, ErrCompressedExif) {
+		t.Fatalf("Compressed Exif must be reported, actual error: %v", err)
+	}
+}
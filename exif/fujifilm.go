@@ -0,0 +1,73 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// Fujifilm maker notes tags
+const (
+	FujiQualityTagID              = 0x1000
+	FujiSaturationTagID           = 0x1003
+	FujiClarityTagID              = 0x100f
+	FujiShadowToneTagID           = 0x1040
+	FujiHighlightToneTagID        = 0x1041
+	FujiLensModulationOptimizerID = 0x1045
+	FujiGrainEffectRoughnessTagID = 0x1047
+	FujiColorChromeEffectTagID    = 0x1048
+	FujiGrainEffectSizeTagID      = 0x104c
+	FujiColorChromeFXBlueTagID    = 0x104e
+	FujiShutterTypeTagID          = 0x1050
+	FujiDynamicRangeTagID         = 0x1400
+	FujiFilmModeTagID             = 0x1401
+	FujiDynamicRangeSettingTagID  = 0x1402
+	FujiDevelopmentDynamicRangeID = 0x1403
+	FujiDRangePriorityTagID       = 0x1443
+	FujiDRangePriorityAutoTagID   = 0x1444
+	FujiDRangePriorityFixedTagID  = 0x1445
+)
+
+const fujiHeader = "FUJIFILM"
+
+var fujiTagNames = map[uint16]string{
+	FujiQualityTagID:              "Quality",
+	FujiSaturationTagID:           "Saturation",
+	FujiClarityTagID:              "Clarity",
+	FujiShadowToneTagID:           "ShadowTone",
+	FujiHighlightToneTagID:        "HighlightTone",
+	FujiLensModulationOptimizerID: "LensModulationOptimizer",
+	FujiGrainEffectRoughnessTagID: "GrainEffectRoughness",
+	FujiColorChromeEffectTagID:    "ColorChromeEffect",
+	FujiGrainEffectSizeTagID:      "GrainEffectSize",
+	FujiColorChromeFXBlueTagID:    "ColorChromeFXBlue",
+	FujiShutterTypeTagID:          "ShutterType",
+	FujiDynamicRangeTagID:         "DynamicRange",
+	FujiFilmModeTagID:             "FilmMode",
+	FujiDynamicRangeSettingTagID:  "DynamicRangeSetting",
+	FujiDevelopmentDynamicRangeID: "DevelopmentDynamicRange",
+	FujiDRangePriorityTagID:       "DRangePriority",
+	FujiDRangePriorityAutoTagID:   "DRangePriorityAuto",
+	FujiDRangePriorityFixedTagID:  "DRangePriorityFixed",
+}
+
+func init() {
+	for id, name := range fujiTagNames {
+		tagNames[fmt.Sprintf("%04x/%04x/%04x", exifTagID, makerNotesTagID, id)] = "Fujifilm " + name
+	}
+}
+
+func fujiDetector(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(fujiHeader))
+}
+
+// Fujifilm maker notes are always little endian. Header is followed by the offset of IFD, all the offsets are
+// relative to the start of maker notes
+func fujiReader(file File, entry ifdEntry) (*ifd, error) {
+	if len(entry.ValueBytes) < len(fujiHeader)+4 {
+		return nil, nil
+	}
+	ifdOffset := binary.LittleEndian.Uint32(entry.ValueBytes[len(fujiHeader):])
+	makerNoteOffset := file.GetTiffHeaderOffset() + int64(entry.Data)
+	return readRelativeIfd(file, makerNoteOffset, int64(ifdOffset), LittleEndian, entry.IfdIndex)
+}
@@ -0,0 +1,40 @@
+package exif
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestReadingFujifilmMakerNotes(t *testing.T) {
+	makerNote := buildMakerNote(binary.LittleEndian, []byte("FUJIFILM\x0c\x00\x00\x00"), []tiffEntry{
+		asciiEntry(FujiQualityTagID, "FINE   "),
+		shortEntry(FujiShutterTypeTagID, 1),
+		shortEntry(FujiFilmModeTagID, 0x800),
+		longEntry(FujiGrainEffectRoughnessTagID, 32),
+	})
+	tiff := buildTiff([]tiffEntry{
+		asciiEntry(0x010f, "FUJIFILM"),
+		{ID: exifTagID, Sub: []tiffEntry{
+			asciiEntry(0x9003, "2021:05:01 12:34:56"),
+			undefinedEntry(makerNotesTagID, makerNote),
+		}},
+	})
+	tagMap := readTestTags(t, writeTempFile(t, ".jpg", buildJpeg(tiff)))
+
+	if tag, ok := tagMap["8769/927c/1000"]; !ok || tag.Value.(string) != "FINE" {
+		t.Fatalf("Failed to read Fujifilm quality")
+	}
+	if tag, ok := tagMap["8769/927c/1401"]; !ok || tag.Value.([]uint16)[0] != 0x800 || tag.Name() != "Fujifilm FilmMode" {
+		t.Fatalf("Failed to read Fujifilm film mode")
+	}
+	if tag, ok := tagMap["8769/927c/1050"]; !ok || tag.Value.([]uint16)[0] != 1 {
+		t.Fatalf("Failed to read Fujifilm shutter type")
+	}
+	if tag, ok := tagMap["8769/927c/1047"]; !ok || tag.Value.([]uint32)[0] != 32 {
+		t.Fatalf("Failed to read Fujifilm grain effect")
+	}
+	// byte order and offsets of the main TIFF structure must be restored after reading maker notes
+	if tag, ok := tagMap["8769/9003"]; !ok || tag.Value.(string) != "2021:05:01 12:34:56" {
+		t.Fatalf("Failed to read Exif after Fujifilm maker notes")
+	}
+}
@@ -0,0 +1,267 @@
+package exif
+
+import (
+	"fmt"
+)
+
+const (
+	// construction methods of items in "iloc" box
+	ilocFileOffset = 0
+	ilocIdatOffset = 1
+)
+
+// itemLocation is a location of the item data as described by "iloc" box
+type itemLocation struct {
+	ConstructionMethod uint16
+	Offset             int64
+	Length             int64
+	ExtentCount        uint16
+}
+
+// readUintN reads unsigned integer of a given size in bytes. Size of 0 means that there is no value
+func readUintN(file File, size uint8) (uint64, error) {
+	switch size {
+	case 0:
+		return 0, nil
+	case 2:
+		value, err := file.readUint16()
+		return uint64(value), err
+	case 4:
+		value, err := file.readUint32()
+		return uint64(value), err
+	case 8:
+		var value uint64
+		err := file.Read(&value)
+		return value, err
+	}
+	return 0, fmt.Errorf("Unsupported integer size %d in %s", size, file.GetPath())
+}
+
+// readFullBoxVersion reads version and flags of a full box and returns version
+func readFullBoxVersion(file File, box *bmffBox) (uint8, error) {
+	_, err := file.seek(box.Start)
+	if err != nil {
+		return 0, err
+	}
+	versionAndFlags, err := file.readUint32()
+	if err != nil {
+		return 0, err
+	}
+	return uint8(versionAndFlags >> 24), nil
+}
+
+// findItemID walks the item entries in "iinf" box and returns the ID of the first item of a given type
+func findItemID(file File, iinf *bmffBox, itemType string) (uint32, bool, error) {
+	version, err := readFullBoxVersion(file, iinf)
+	if err != nil {
+		return 0, false, err
+	}
+	if version == 0 {
+		_, err = file.readUint16()
+	} else {
+		_, err = file.readUint32()
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	pos, err := file.currentPosition()
+	if err != nil {
+		return 0, false, err
+	}
+	entries, err := readBoxes(file, pos, iinf.End)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, entry := range entries {
+		if entry.Type != "infe" {
+			continue
+		}
+		version, err := readFullBoxVersion(file, &entry)
+		if err != nil {
+			return 0, false, err
+		}
+		if version < 2 { // older versions of item info entry do not have item type
+			continue
+		}
+		var itemID uint32
+		if version == 2 {
+			id, err := file.readUint16()
+			if err != nil {
+				return 0, false, err
+			}
+			itemID = uint32(id)
+		} else {
+			itemID, err = file.readUint32()
+			if err != nil {
+				return 0, false, err
+			}
+		}
+		_, err = file.readUint16() // item protection index
+		if err != nil {
+			return 0, false, err
+		}
+		entryType, err := file.readBytes(4)
+		if err != nil {
+			return 0, false, err
+		}
+		if string(entryType) == itemType {
+			return itemID, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// findItemLocation reads "iloc" box and returns location of the item with a given ID
+func findItemLocation(file File, iloc *bmffBox, itemID uint32) (*itemLocation, error) {
+	version, err := readFullBoxVersion(file, iloc)
+	if err != nil {
+		return nil, err
+	}
+	sizes, err := file.readUint16()
+	if err != nil {
+		return nil, err
+	}
+	offsetSize := uint8(sizes >> 12)
+	lengthSize := uint8(sizes>>8) & 0xF
+	baseOffsetSize := uint8(sizes>>4) & 0xF
+	indexSize := uint8(0)
+	if version == 1 || version == 2 {
+		indexSize = uint8(sizes) & 0xF
+	}
+	var itemCount uint32
+	if version < 2 {
+		count, err := file.readUint16()
+		if err != nil {
+			return nil, err
+		}
+		itemCount = uint32(count)
+	} else {
+		itemCount, err = file.readUint32()
+		if err != nil {
+			return nil, err
+		}
+	}
+	for item := uint32(0); item < itemCount; item++ {
+		var id uint32
+		if version < 2 {
+			id16, err := file.readUint16()
+			if err != nil {
+				return nil, err
+			}
+			id = uint32(id16)
+		} else {
+			id, err = file.readUint32()
+			if err != nil {
+				return nil, err
+			}
+		}
+		location := &itemLocation{}
+		if version == 1 || version == 2 {
+			method, err := file.readUint16()
+			if err != nil {
+				return nil, err
+			}
+			location.ConstructionMethod = method & 0xF
+		}
+		_, err = file.readUint16() // data reference index
+		if err != nil {
+			return nil, err
+		}
+		baseOffset, err := readUintN(file, baseOffsetSize)
+		if err != nil {
+			return nil, err
+		}
+		location.ExtentCount, err = file.readUint16()
+		if err != nil {
+			return nil, err
+		}
+		for extent := uint16(0); extent < location.ExtentCount; extent++ {
+			_, err = readUintN(file, indexSize)
+			if err != nil {
+				return nil, err
+			}
+			extentOffset, err := readUintN(file, offsetSize)
+			if err != nil {
+				return nil, err
+			}
+			extentLength, err := readUintN(file, lengthSize)
+			if err != nil {
+				return nil, err
+			}
+			if extent == 0 {
+				location.Offset = int64(baseOffset + extentOffset)
+				location.Length = int64(extentLength)
+			}
+		}
+		if id == itemID {
+			return location, nil
+		}
+	}
+	return nil, fmt.Errorf("No location of item %d in %s", itemID, file.GetPath())
+}
+
+// readHeifTags reads tags from HEIF based files (HEIC, AVIF). Exif is stored as an item of type "Exif", which is
+// found through "iinf" and "iloc" boxes in "meta" box. Item data starts with 4-byte offset to TIFF header
+func readHeifTags(file File) (Tags, error) {
+	topBoxes, err := readTopLevelBoxes(file)
+	if err != nil {
+		return nil, err
+	}
+	meta := findBox(topBoxes, "meta")
+	if meta == nil {
+		return nil, fmt.Errorf("No 'meta' box in %s", file.GetPath())
+	}
+	metaBoxes, err := readBoxes(file, meta.Start+4, meta.End) // meta is a full box
+	if err != nil {
+		return nil, err
+	}
+	iinf := findBox(metaBoxes, "iinf")
+	iloc := findBox(metaBoxes, "iloc")
+	if iinf == nil || iloc == nil {
+		return nil, fmt.Errorf("No item information in %s", file.GetPath())
+	}
+	exifID, ok, err := findItemID(file, iinf, "Exif")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// file does not contain exif
+		return make(Tags, 0), nil
+	}
+	location, err := findItemLocation(file, iloc, exifID)
+	if err != nil {
+		return nil, err
+	}
+	if location.ExtentCount != 1 {
+		return nil, fmt.Errorf("Fragmented Exif item is not supported in %s", file.GetPath())
+	}
+	offset := location.Offset
+	switch location.ConstructionMethod {
+	case ilocFileOffset:
+	case ilocIdatOffset:
+		idat := findBox(metaBoxes, "idat")
+		if idat == nil {
+			return nil, fmt.Errorf("No 'idat' box in %s", file.GetPath())
+		}
+		offset += idat.Start
+	default:
+		return nil, fmt.Errorf("Unsupported Exif item construction method %d in %s", location.ConstructionMethod, file.GetPath())
+	}
+
+	return readTiffBlockWithOffsetPrefix(file, offset)
+}
+
+// readTiffBlockWithOffsetPrefix reads TIFF block which starts with 4-byte offset to the TIFF header. Such blocks are
+// used to store Exif in HEIF and JPEG XL files
+func readTiffBlockWithOffsetPrefix(file File, offset int64) (Tags, error) {
+	_, err := file.seek(offset)
+	if err != nil {
+		return nil, err
+	}
+	file.SetOrder(BigEndian)
+	tiffHeaderOffset, err := file.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	return readTiffBlock(file, offset+4+int64(tiffHeaderOffset))
+}
@@ -0,0 +1,56 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func buildInfe(itemID uint16, itemType string) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{2, 0, 0, 0}) // version 2
+	binary.Write(&buf, binary.BigEndian, itemID)
+	binary.Write(&buf, binary.BigEndian, uint16(0))
+	buf.WriteString(itemType)
+	buf.WriteByte(0) // empty item name
+	return buildBox("infe", buf.Bytes())
+}
+
+func buildIloc(itemID uint16, offset uint32, length uint32) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0, 0, 0, 0}) // version 0
+	buf.Write([]byte{0x44, 0x00}) // offset size 4, length size 4, base offset size 0
+	binary.Write(&buf, binary.BigEndian, uint16(1))
+	binary.Write(&buf, binary.BigEndian, itemID)
+	binary.Write(&buf, binary.BigEndian, uint16(0)) // data reference index
+	binary.Write(&buf, binary.BigEndian, uint16(1)) // extent count
+	binary.Write(&buf, binary.BigEndian, offset)
+	binary.Write(&buf, binary.BigEndian, length)
+	return buildBox("iloc", buf.Bytes())
+}
+
+func buildHeif(brand string, exifItem []byte) []byte {
+	build := func(offset uint32) [][]byte {
+		iinf := buildBox("iinf", []byte{0, 0, 0, 0, 0, 2}, buildInfe(1, "hvc1"), buildInfe(2, "Exif"))
+		meta := buildBox("meta", []byte{0, 0, 0, 0}, iinf, buildIloc(2, offset, uint32(len(exifItem))))
+		return [][]byte{buildFtyp(brand), meta}
+	}
+	// size of the boxes does not depend on the offset value
+	offset := len(bytes.Join(build(0), nil)) + 8
+	return bytes.Join(append(build(uint32(offset)), buildBox("mdat", exifItem)), nil)
+}
+
+func TestReadingHeif(t *testing.T) {
+	tiff := buildTiff([]tiffEntry{
+		asciiEntry(0x010f, "Apple"),
+		asciiEntry(0x0110, "iPhone 12"),
+	})
+	exifItem := append([]byte{0, 0, 0, 6, 'E', 'x', 'i', 'f', 0, 0}, tiff...)
+
+	for _, brand := range []string{"heic", "avif"} {
+		tagMap := readTestTags(t, writeTempFile(t, "."+brand, buildHeif(brand, exifItem)))
+		if tag, ok := tagMap[model]; !ok || tag.Value.(string) != "iPhone 12" {
+			t.Fatalf("Failed to read model from %s file: %v", brand, tagMap)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package exif
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/uaraven/exif-stat/logger"
+)
+
+const (
+	iccIdentifier       = "ICC_PROFILE"
+	iccHeaderSize       = 128
+	iccTagEntrySize     = 12
+	iccDescriptionTag   = "desc"
+	iccTextDescription  = "desc" // ICC v2 textDescriptionType
+	iccMultiLocalized   = "mluc" // ICC v4 multiLocalizedUnicodeType
+	iccChunkHeaderSize  = 2      // sequence number and number of chunks
+	iccColorSpaceOffset = 16
+)
+
+// IccProfile contains the information from ICC colour profile
+type IccProfile struct {
+	// Colour space of the data, i.e. "RGB" or "CMYK"
+	ColorSpace string
+	// Profile description, i.e. "sRGB IEC61966-2.1"
+	Description string
+}
+
+// ParseIccProfile parses header and description tag of ICC profile
+func ParseIccProfile(data []byte) (*IccProfile, error) {
+	if len(data) < iccHeaderSize+4 {
+		return nil, fmt.Errorf("ICC profile is too short")
+	}
+	profile := &IccProfile{
+		ColorSpace: strings.TrimSpace(string(data[iccColorSpaceOffset : iccColorSpaceOffset+4])),
+	}
+	tagCount := int(binary.BigEndian.Uint32(data[iccHeaderSize:]))
+	for i := 0; i < tagCount; i++ {
+		pos := iccHeaderSize + 4 + i*iccTagEntrySize
+		if pos+iccTagEntrySize > len(data) {
+			return nil, fmt.Errorf("ICC tag table is out of bounds")
+		}
+		if string(data[pos:pos+4]) != iccDescriptionTag {
+			continue
+		}
+		offset := int(binary.BigEndian.Uint32(data[pos+4:]))
+		size := int(binary.BigEndian.Uint32(data[pos+8:]))
+		if offset < 0 || size < 0 || offset+size > len(data) {
+			return nil, fmt.Errorf("ICC description tag is out of bounds")
+		}
+		profile.Description = parseIccText(data[offset : offset+size])
+	}
+	return profile, nil
+}
+
+// parseIccText decodes text of textDescriptionType or the first record of multiLocalizedUnicodeType
+func parseIccText(data []byte) string {
+	if len(data) < 12 {
+		return ""
+	}
+	switch string(data[0:4]) {
+	case iccTextDescription:
+		size := int(binary.BigEndian.Uint32(data[8:]))
+		if 12+size > len(data) {
+			return ""
+		}
+		return strings.TrimRight(string(data[12:12+size]), "\x00")
+	case iccMultiLocalized:
+		if len(data) < 28 || binary.BigEndian.Uint32(data[8:]) == 0 {
+			return ""
+		}
+		size := int(binary.BigEndian.Uint32(data[20:]))
+		offset := int(binary.BigEndian.Uint32(data[24:]))
+		if offset+size > len(data) {
+			return ""
+		}
+		text := make([]uint16, size/2)
+		for i := range text {
+			text[i] = binary.BigEndian.Uint16(data[offset+i*2:])
+		}
+		return strings.TrimRight(string(utf16.Decode(text)), "\x00")
+	}
+	return ""
+}
+
+// readIccSegment reads a chunk of ICC profile from APP2 segment. Large profiles are split between several segments,
+// the profile is parsed when all the chunks are read
+func readIccSegment(file File, marker *marker, metadata *Metadata) error {
+	data, err := readSegmentData(file, marker, iccIdentifier)
+	if err != nil {
+		return err
+	}
+	if len(data) < iccChunkHeaderSize {
+		return nil
+	}
+	sequence, count := int(data[0]), int(data[1])
+	if sequence < 1 || sequence > count {
+		return nil
+	}
+	if metadata.iccChunks == nil {
+		metadata.iccChunks = make([][]byte, count)
+	}
+	if len(metadata.iccChunks) != count {
+		return nil
+	}
+	metadata.iccChunks[sequence-1] = data[iccChunkHeaderSize:]
+	profile := make([]byte, 0)
+	for _, chunk := range metadata.iccChunks {
+		if chunk == nil {
+			return nil
+		}
+		profile = append(profile, chunk...)
+	}
+	metadata.IccProfile, err = ParseIccProfile(profile)
+	if err != nil {
+		logger.Verbose(2, fmt.Sprintf("Invalid ICC profile in %s: %v", file.GetPath(), err))
+	}
+	return nil
+}
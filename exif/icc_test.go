@@ -0,0 +1,116 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+)
+
+// buildIccProfile creates minimal ICC profile with a single description tag
+func buildIccProfile(desc []byte) []byte {
+	var buf bytes.Buffer
+	header := make([]byte, iccHeaderSize)
+	copy(header[iccColorSpaceOffset:], "RGB ")
+	buf.Write(header)
+	binary.Write(&buf, binary.BigEndian, uint32(1))
+	buf.WriteString(iccDescriptionTag)
+	binary.Write(&buf, binary.BigEndian, uint32(iccHeaderSize+4+iccTagEntrySize))
+	binary.Write(&buf, binary.BigEndian, uint32(len(desc)))
+	buf.Write(desc)
+	binary.BigEndian.PutUint32(buf.Bytes()[0:], uint32(buf.Len()))
+	return buf.Bytes()
+}
+
+func textDescription(text string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(iccTextDescription)
+	buf.Write([]byte{0, 0, 0, 0})
+	binary.Write(&buf, binary.BigEndian, uint32(len(text)+1))
+	buf.WriteString(text)
+	buf.WriteByte(0)
+	return buf.Bytes()
+}
+
+func multiLocalizedDescription(text string) []byte {
+	var buf bytes.Buffer
+	encoded := utf16.Encode([]rune(text))
+	buf.WriteString(iccMultiLocalized)
+	buf.Write([]byte{0, 0, 0, 0})
+	binary.Write(&buf, binary.BigEndian, uint32(1))
+	binary.Write(&buf, binary.BigEndian, uint32(12))
+	buf.WriteString("enUS")
+	binary.Write(&buf, binary.BigEndian, uint32(len(encoded)*2))
+	binary.Write(&buf, binary.BigEndian, uint32(28))
+	binary.Write(&buf, binary.BigEndian, encoded)
+	return buf.Bytes()
+}
+
+func iccSegment(sequence byte, count byte, data []byte) []byte {
+	payload := append([]byte(iccIdentifier+"\x00"), sequence, count)
+	return jpegSegment(app2Marker, append(payload, data...))
+}
+
+func TestReadingIccProfileChunks(t *testing.T) {
+	profile := buildIccProfile(textDescription("Adobe RGB (1998)"))
+	split := len(profile) / 2
+	// chunks are not required to be in order
+	jpeg := buildJpegWithSegments(iccSegment(2, 2, profile[split:]), iccSegment(1, 2, profile[:split]))
+
+	file, err := OpenExifFileIo(writeTempFile(t, ".jpg", jpeg))
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+
+	metadata, err := ReadMetadata(file)
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	if metadata.IccProfile == nil {
+		t.Fatalf("Failed to read ICC profile")
+	}
+	if metadata.IccProfile.Description != "Adobe RGB (1998)" || metadata.IccProfile.ColorSpace != "RGB" {
+		t.Fatalf("Invalid ICC profile: %v", metadata.IccProfile)
+	}
+}
+
+func TestReadingTruncatedIccSegment(t *testing.T) {
+	segment := jpegSegment(app2Marker, []byte(iccIdentifier))
+	file, err := OpenExifFileIo(writeTempFile(t, ".jpg", buildJpegWithSegments(segment)))
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+
+	_, err = ReadMetadata(file)
+	if err == nil {
+		t.Fatalf("Truncated ICC segment must be reported")
+	}
+}
+
+func TestParsingIccV4Description(t *testing.T) {
+	profile, err := ParseIccProfile(buildIccProfile(multiLocalizedDescription("Display P3")))
+	if err != nil {
+		t.Fatalf("Failed to parse ICC profile: %v", err)
+	}
+	if profile.Description != "Display P3" {
+		t.Fatalf("Invalid description: '%s'", profile.Description)
+	}
+}
+
+func TestReadingInteropIfd(t *testing.T) {
+	tiff := buildTiff([]tiffEntry{
+		{ID: exifTagID, Sub: []tiffEntry{
+			shortEntry(0xa001, 0xFFFF),
+			{ID: interopTagID, Sub: []tiffEntry{asciiEntry(0x0001, "R03")}},
+		}},
+	})
+	tagMap := readTestTags(t, writeTempFile(t, ".jpg", buildJpeg(tiff)))
+	if tag, ok := tagMap[interopIndex]; !ok || tag.Value.(string) != "R03" {
+		t.Fatalf("Failed to read interoperability index")
+	}
+	if tag, ok := tagMap[colorSpace]; !ok || tag.Value.([]uint16)[0] != 0xFFFF {
+		t.Fatalf("Failed to read color space")
+	}
+}
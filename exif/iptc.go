@@ -0,0 +1,122 @@
+package exif
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/uaraven/exif-stat/logger"
+)
+
+// IPTC datasets of the application record (record 2). Dataset ID is record number in the high byte and dataset
+// number in the low byte
+const (
+	IptcKeywords  uint16 = 0x0219
+	IptcByline    uint16 = 0x0250
+	IptcCopyright uint16 = 0x0274
+	IptcCaption   uint16 = 0x0278
+)
+
+const (
+	photoshopIdentifier   = "Photoshop 3.0"
+	photoshopResourceType = "8BIM"
+	iptcResourceID        = 0x0404
+	iptcTagMarker         = 0x1C
+)
+
+// IptcProperties maps IPTC dataset IDs to their values. Repeatable datasets, like keywords, have multiple values
+type IptcProperties map[uint16][]string
+
+// Get returns the first value of the dataset
+func (iptc IptcProperties) Get(id uint16) (string, bool) {
+	values, ok := iptc[id]
+	if !ok || len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// ParseIptc parses IPTC-IIM datasets. Values are expected to be UTF-8 or ASCII encoded
+func ParseIptc(data []byte) (IptcProperties, error) {
+	iptc := make(IptcProperties)
+	pos := 0
+	for pos+5 <= len(data) {
+		if data[pos] != iptcTagMarker {
+			return nil, fmt.Errorf("Invalid IPTC tag marker at %d", pos)
+		}
+		id := binary.BigEndian.Uint16(data[pos+1 : pos+3])
+		size := int(binary.BigEndian.Uint16(data[pos+3 : pos+5]))
+		pos += 5
+		if size&0x8000 != 0 { // extended dataset, size field contains length of the actual size
+			sizeLength := size & 0x7FFF
+			if sizeLength > 4 || pos+sizeLength > len(data) {
+				return nil, fmt.Errorf("Invalid IPTC extended dataset size at %d", pos)
+			}
+			size = 0
+			for _, b := range data[pos : pos+sizeLength] {
+				size = size<<8 | int(b)
+			}
+			pos += sizeLength
+		}
+		if size < 0 || pos+size > len(data) {
+			return nil, fmt.Errorf("IPTC dataset %04x is out of bounds", id)
+		}
+		iptc[id] = append(iptc[id], string(data[pos:pos+size]))
+		pos += size
+	}
+	return iptc, nil
+}
+
+// findIptcResource finds IPTC-NAA block in the list of Photoshop image resources
+func findIptcResource(data []byte) []byte {
+	pos := 0
+	for pos+12 <= len(data) && string(data[pos:pos+4]) == photoshopResourceType {
+		id := binary.BigEndian.Uint16(data[pos+4 : pos+6])
+		pos += 6
+		// name is a pascal string, padded to even size including length byte
+		nameSize := int(data[pos]) + 1
+		pos += nameSize + nameSize%2
+		if pos+4 > len(data) {
+			return nil
+		}
+		size := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		pos += 4
+		if size < 0 || pos+size > len(data) {
+			return nil
+		}
+		if id == iptcResourceID {
+			return data[pos : pos+size]
+		}
+		pos += size + size%2
+	}
+	return nil
+}
+
+// readApp13Segment reads IPTC data from Photoshop image resources stored in APP13 segment. As with XMP, invalid IPTC
+// data is reported, but does not fail reading of the file
+func readApp13Segment(file File, marker *marker, metadata *Metadata) error {
+	identifier, err := readSegmentIdentifier(file, marker)
+	if err != nil || identifier != photoshopIdentifier {
+		return err
+	}
+	data, err := readSegmentData(file, marker, identifier)
+	if err != nil {
+		return err
+	}
+	iptcData := findIptcResource(data)
+	if iptcData == nil {
+		return nil
+	}
+	iptc, err := ParseIptc(iptcData)
+	if err != nil {
+		logger.Verbose(2, fmt.Sprintf("Invalid IPTC in %s: %v", file.GetPath(), err))
+		return nil
+	}
+	if metadata.Iptc == nil {
+		metadata.Iptc = iptc
+	} else {
+		for id, values := range iptc {
+			metadata.Iptc[id] = append(metadata.Iptc[id], values...)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func iptcDataSet(id uint16, value string) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(iptcTagMarker)
+	binary.Write(&buf, binary.BigEndian, id)
+	binary.Write(&buf, binary.BigEndian, uint16(len(value)))
+	buf.WriteString(value)
+	return buf.Bytes()
+}
+
+// buildApp13 creates APP13 segment payload with a single 8BIM resource containing IPTC data
+func buildApp13(iptc []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(photoshopIdentifier)
+	buf.WriteByte(0)
+	// unrelated resource which must be skipped
+	buf.WriteString(photoshopResourceType)
+	binary.Write(&buf, binary.BigEndian, uint16(0x03ED))
+	buf.Write([]byte{0, 0})
+	binary.Write(&buf, binary.BigEndian, uint32(3))
+	buf.Write([]byte{1, 2, 3, 0})
+	buf.WriteString(photoshopResourceType)
+	binary.Write(&buf, binary.BigEndian, uint16(iptcResourceID))
+	buf.Write([]byte{0, 0})
+	binary.Write(&buf, binary.BigEndian, uint32(len(iptc)))
+	buf.Write(iptc)
+	if len(iptc)%2 != 0 {
+		buf.WriteByte(0)
+	}
+	return buf.Bytes()
+}
+
+func TestReadingIptc(t *testing.T) {
+	var iptc []byte
+	iptc = append(iptc, iptcDataSet(0x0100|90, "\x1b%G")...)
+	iptc = append(iptc, iptcDataSet(IptcKeywords, "travel")...)
+	iptc = append(iptc, iptcDataSet(IptcKeywords, "mountains")...)
+	iptc = append(iptc, iptcDataSet(IptcCaption, "Sunrise over the \"Alps\"")...)
+	iptc = append(iptc, iptcDataSet(IptcByline, "Jane Doe")...)
+	iptc = append(iptc, iptcDataSet(IptcCopyright, "(c) Agency")...)
+	tiff := buildTiff([]tiffEntry{asciiEntry(0x010f, "TestMake")})
+	jpeg := buildJpegWithSegments(
+		jpegSegment(exifDataMarker, append([]byte{'E', 'x', 'i', 'f', 0, 0}, tiff...)),
+		jpegSegment(app13Marker, buildApp13(iptc)))
+
+	file, err := OpenExifFileIo(writeTempFile(t, ".jpg", jpeg))
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+
+	metadata, err := ReadMetadata(file)
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	if keywords := metadata.Iptc[IptcKeywords]; len(keywords) != 2 || keywords[0] != "travel" || keywords[1] != "mountains" {
+		t.Fatalf("Invalid keywords: %v", keywords)
+	}
+	if value, ok := metadata.Iptc.Get(IptcCaption); !ok || value != "Sunrise over the \"Alps\"" {
+		t.Fatalf("Invalid caption: %v", value)
+	}
+	if value, ok := metadata.Iptc.Get(IptcByline); !ok || value != "Jane Doe" {
+		t.Fatalf("Invalid byline: %v", value)
+	}
+	if value, ok := metadata.Iptc.Get(IptcCopyright); !ok || value != "(c) Agency" {
+		t.Fatalf("Invalid copyright: %v", value)
+	}
+	if _, ok := TagsAsMap(metadata.Tags)[cameraMake]; !ok {
+		t.Fatalf("Failed to read Exif along with IPTC")
+	}
+}
+
+func TestParsingInvalidIptc(t *testing.T) {
+	_, err := ParseIptc([]byte{iptcTagMarker, 0x02, 0x19, 0x00, 0x10, 'a'})
+	if err == nil {
+		t.Fatalf("Dataset out of bounds must be reported")
+	}
+}
+
+func TestReadingTruncatedApp13Segment(t *testing.T) {
+	segment := jpegSegment(app13Marker, []byte(photoshopIdentifier))
+	file, err := OpenExifFileIo(writeTempFile(t, ".jpg", buildJpegWithSegments(segment)))
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+
+	_, err = ReadMetadata(file)
+	if err == nil {
+		t.Fatalf("Truncated APP13 segment must be reported")
+	}
+}
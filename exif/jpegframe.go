@@ -0,0 +1,121 @@
+package exif
+
+import (
+	"fmt"
+	"strings"
+)
+
+// start of frame markers of the supported Huffman-coded JPEG modes
+const (
+	sof0Marker = 0xFFC0 // baseline
+	sof1Marker = 0xFFC1 // extended sequential
+	sof2Marker = 0xFFC2 // progressive
+)
+
+// JPEG coding modes
+const (
+	JpegBaseline    = "Baseline"
+	JpegExtended    = "Extended"
+	JpegProgressive = "Progressive"
+)
+
+var jpegModes = map[uint16]string{
+	sof0Marker: JpegBaseline,
+	sof1Marker: JpegExtended,
+	sof2Marker: JpegProgressive,
+}
+
+// names of chroma subsampling modes by the sampling factors of luma component, when both chroma components have 1x1
+// sampling factors
+var chromaSubsamplings = map[[2]byte]string{
+	{1, 1}: "4:4:4",
+	{2, 1}: "4:2:2",
+	{2, 2}: "4:2:0",
+	{1, 2}: "4:4:0",
+	{4, 1}: "4:1:1",
+}
+
+// JpegComponent describes a colour component of JPEG frame
+type JpegComponent struct {
+	ID                 byte
+	HorizontalSampling byte
+	VerticalSampling   byte
+	QuantizationTable  byte
+}
+
+// JpegFrame contains information from JPEG start of frame header
+type JpegFrame struct {
+	// Coding mode, one of JpegBaseline, JpegExtended or JpegProgressive
+	Mode       string
+	Precision  byte
+	Width      uint16
+	Height     uint16
+	Components []JpegComponent
+}
+
+// Progressive returns true for progressive JPEG images
+func (frame *JpegFrame) Progressive() bool {
+	return frame.Mode == JpegProgressive
+}
+
+// Subsampling returns chroma subsampling in J:a:b notation, i.e. "4:2:0". Sampling factors of all components are
+// returned if subsampling does not have a common name
+func (frame *JpegFrame) Subsampling() string {
+	if len(frame.Components) == 1 {
+		return "Grayscale"
+	}
+	if len(frame.Components) == 3 {
+		y, cb, cr := frame.Components[0], frame.Components[1], frame.Components[2]
+		chromaFullResolution := cb.HorizontalSampling == 1 && cb.VerticalSampling == 1 &&
+			cr.HorizontalSampling == 1 && cr.VerticalSampling == 1
+		if name, ok := chromaSubsamplings[[2]byte{y.HorizontalSampling, y.VerticalSampling}]; ok && chromaFullResolution {
+			return name
+		}
+	}
+	factors := make([]string, len(frame.Components))
+	for i, component := range frame.Components {
+		factors[i] = fmt.Sprintf("%dx%d", component.HorizontalSampling, component.VerticalSampling)
+	}
+	return strings.Join(factors, ",")
+}
+
+// readSofSegment decodes JPEG frame header. Only the frame of the main image is read, frames of the embedded
+// thumbnails are not visited by the marker walk
+func readSofSegment(file File, marker *marker, metadata *Metadata) error {
+	if metadata.Frame != nil {
+		return nil
+	}
+	_, err := file.seek(marker.Offset)
+	if err != nil {
+		return err
+	}
+	header, err := file.readBytes(6)
+	if err != nil {
+		return err
+	}
+	frame := &JpegFrame{
+		Mode:      jpegModes[marker.Marker],
+		Precision: header[0],
+		Height:    uint16(header[1])<<8 | uint16(header[2]),
+		Width:     uint16(header[3])<<8 | uint16(header[4]),
+	}
+	componentCount := header[5]
+	if int(marker.Size) < 8+int(componentCount)*3 {
+		return fmt.Errorf("Invalid JPEG frame header size %d", marker.Size)
+	}
+	components, err := file.readBytes(uint16(componentCount) * 3)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < int(componentCount); i++ {
+		component := components[i*3 : i*3+3]
+		frame.Components = append(frame.Components, JpegComponent{
+			ID:                 component[0],
+			HorizontalSampling: component[1] >> 4,
+			VerticalSampling:   component[1] & 0x0F,
+			QuantizationTable:  component[2],
+		})
+	}
+	metadata.Frame = frame
+	return nil
+}
@@ -0,0 +1,56 @@
+package exif
+
+import (
+	"testing"
+)
+
+func sofSegment(marker uint16, width uint16, height uint16, sampling ...byte) []byte {
+	data := []byte{8, byte(height >> 8), byte(height), byte(width >> 8), byte(width), byte(len(sampling))}
+	for i, factors := range sampling {
+		data = append(data, byte(i+1), factors, 0)
+	}
+	return jpegSegment(marker, data)
+}
+
+func TestReadingJpegFrame(t *testing.T) {
+	tiff := buildTiff([]tiffEntry{asciiEntry(0x010f, "TestMake")})
+	jpeg := buildJpegWithSegments(
+		jpegSegment(exifDataMarker, append([]byte{'E', 'x', 'i', 'f', 0, 0}, tiff...)),
+		sofSegment(sof2Marker, 6016, 4016, 0x22, 0x11, 0x11))
+
+	file, err := OpenExifFileIo(writeTempFile(t, ".jpg", jpeg))
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+
+	metadata, err := ReadMetadata(file)
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	frame := metadata.Frame
+	if frame == nil {
+		t.Fatalf("Failed to read JPEG frame")
+	}
+	if frame.Width != 6016 || frame.Height != 4016 || !frame.Progressive() || frame.Precision != 8 {
+		t.Fatalf("Invalid JPEG frame: %v", frame)
+	}
+	if frame.Subsampling() != "4:2:0" {
+		t.Fatalf("Invalid subsampling: %s", frame.Subsampling())
+	}
+}
+
+func TestJpegFrameSubsampling(t *testing.T) {
+	frame := &JpegFrame{Components: []JpegComponent{
+		{HorizontalSampling: 2, VerticalSampling: 1},
+		{HorizontalSampling: 1, VerticalSampling: 1},
+		{HorizontalSampling: 1, VerticalSampling: 1},
+	}}
+	if frame.Subsampling() != "4:2:2" {
+		t.Fatalf("Invalid subsampling: %s", frame.Subsampling())
+	}
+	frame.Components[2].VerticalSampling = 2
+	if frame.Subsampling() != "2x1,1x1,1x2" {
+		t.Fatalf("Invalid subsampling: %s", frame.Subsampling())
+	}
+}
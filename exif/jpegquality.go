@@ -0,0 +1,187 @@
+package exif
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+)
+
+const dqtMarker = 0xFFDB
+
+// Tables are indexed by their ID in DQT segment. Camera and IJG encoders use table 0 for luma and table 1 for chroma
+const (
+	lumaTableID   = 0
+	chromaTableID = 1
+)
+
+// zigzag order of DCT coefficients, maps position in DQT segment to the position in 8x8 block
+var jpegZigzag = [64]int{
+	0, 1, 8, 16, 9, 2, 3, 10, 17, 24, 32, 25, 18, 11, 4, 5,
+	12, 19, 26, 33, 40, 48, 41, 34, 27, 20, 13, 6, 7, 14, 21, 28,
+	35, 42, 49, 56, 57, 50, 43, 36, 29, 22, 15, 23, 30, 37, 44, 51,
+	58, 59, 52, 45, 38, 31, 39, 46, 53, 60, 61, 54, 47, 55, 62, 63,
+}
+
+// standard quantization tables from JPEG specification (Annex K), used by IJG library for quality 50
+var (
+	standardLumaTable = [64]uint16{
+		16, 11, 10, 16, 24, 40, 51, 61,
+		12, 12, 14, 19, 26, 58, 60, 55,
+		14, 13, 16, 24, 40, 57, 69, 56,
+		14, 17, 22, 29, 51, 87, 80, 62,
+		18, 22, 37, 56, 68, 109, 103, 77,
+		24, 35, 55, 64, 81, 104, 113, 92,
+		49, 64, 78, 87, 103, 121, 120, 101,
+		72, 92, 95, 98, 112, 100, 103, 99,
+	}
+	standardChromaTable = [64]uint16{
+		17, 18, 24, 47, 99, 99, 99, 99,
+		18, 21, 26, 66, 99, 99, 99, 99,
+		24, 26, 56, 99, 99, 99, 99, 99,
+		47, 66, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+	}
+)
+
+// JpegTablesIJG is reported for the images which use tables of IJG library, this usually means that the image was
+// saved by software
+const JpegTablesIJG = "IJG"
+
+// fingerprints of the known non-IJG quantization tables, see quantTablesHash. Each hash is calculated from the tables
+// of a sample file with known origin, sample is given in the comment. Camera tables cannot be taken from the files
+// edited by software, only unmodified in-camera JPEGs can be used as samples
+var knownQuantTables = map[uint64]string{
+	0x498f5376e178d5c2: "ACD Systems", // test-data/cameras/KonicaMinolta/DimageZ3.jpg and Olympus/C760UZ.JPG
+	0xbabfea0f6f260412: "ACD Systems", // test-data/scan/P1020297.JPG
+}
+
+// QuantizationTables contains JPEG quantization tables in natural (row-major) order, indexed by table ID
+type QuantizationTables map[byte][]uint16
+
+// readDqtSegment reads all quantization tables defined in DQT segment
+func readDqtSegment(file File, marker *marker, metadata *Metadata) error {
+	if marker.Size < 2 {
+		return fmt.Errorf("Invalid size of DQT segment: %d", marker.Size)
+	}
+	_, err := file.seek(marker.Offset)
+	if err != nil {
+		return err
+	}
+	data, err := file.readBytes(marker.Size - 2)
+	if err != nil {
+		return err
+	}
+	if metadata.QuantizationTables == nil {
+		metadata.QuantizationTables = make(QuantizationTables)
+	}
+	for pos := 0; pos < len(data); {
+		precision, id := data[pos]>>4, data[pos]&0x0F
+		pos++
+		elementSize := 1
+		if precision != 0 {
+			elementSize = 2
+		}
+		if pos+64*elementSize > len(data) {
+			return fmt.Errorf("Quantization table %d is out of bounds", id)
+		}
+		table := make([]uint16, 64)
+		for i := 0; i < 64; i++ {
+			if elementSize == 1 {
+				table[jpegZigzag[i]] = uint16(data[pos+i])
+			} else {
+				table[jpegZigzag[i]] = uint16(data[pos+i*2])<<8 | uint16(data[pos+i*2+1])
+			}
+		}
+		pos += 64 * elementSize
+		metadata.QuantizationTables[id] = table
+	}
+	return nil
+}
+
+// ijgScale scales standard table value the same way IJG library does for a given quality
+func ijgScale(value uint16, quality int) uint16 {
+	scale := 200 - quality*2
+	if quality < 50 {
+		scale = 5000 / quality
+	}
+	scaled := (int(value)*scale + 50) / 100
+	if scaled < 1 {
+		scaled = 1
+	}
+	return uint16(scaled)
+}
+
+func isIjgTable(table []uint16, standard *[64]uint16, quality int) bool {
+	for i, value := range table {
+		scaled := ijgScale(standard[i], quality)
+		// baseline IJG tables are limited to 8 bits
+		if value != scaled && !(scaled > 255 && value == 255) {
+			return false
+		}
+	}
+	return true
+}
+
+func sumTable(table []uint16) float64 {
+	sum := 0.0
+	for _, value := range table {
+		sum += float64(value)
+	}
+	return sum
+}
+
+// EstimateJpegQuality estimates IJG-equivalent quality factor (1-100) of the image with given quantization tables.
+// exact is true if tables are the IJG tables for the returned quality. Quality of the other tables is estimated by
+// comparing their average scale to the standard tables
+func EstimateJpegQuality(tables QuantizationTables) (quality int, exact bool) {
+	luma, ok := tables[lumaTableID]
+	if !ok || len(luma) != 64 {
+		return 0, false
+	}
+	chroma, hasChroma := tables[chromaTableID]
+	hasChroma = hasChroma && len(chroma) == 64
+	for quality = 100; quality > 0; quality-- {
+		if isIjgTable(luma, &standardLumaTable, quality) && (!hasChroma || isIjgTable(chroma, &standardChromaTable, quality)) {
+			return quality, true
+		}
+	}
+	actual, standard := sumTable(luma), sumTable(standardLumaTable[:])
+	if hasChroma {
+		actual += sumTable(chroma)
+		standard += sumTable(standardChromaTable[:])
+	}
+	scale := actual * 100 / standard
+	var estimate float64
+	if scale <= 100 {
+		estimate = (200 - scale) / 2
+	} else {
+		estimate = 5000 / scale
+	}
+	return int(math.Max(1, math.Min(100, math.Round(estimate)))), false
+}
+
+// quantTablesHash is a FNV-1a hash of luma and chroma tables in natural order
+func quantTablesHash(tables QuantizationTables) uint64 {
+	hash := fnv.New64a()
+	for _, id := range []byte{lumaTableID, chromaTableID} {
+		for _, value := range tables[id] {
+			hash.Write([]byte{byte(value >> 8), byte(value)})
+		}
+	}
+	return hash.Sum64()
+}
+
+// IdentifyQuantTables returns the name of the encoder which is known to use given quantization tables, JpegTablesIJG
+// for the tables of IJG library or empty string if tables are not known
+func IdentifyQuantTables(tables QuantizationTables) string {
+	if name, ok := knownQuantTables[quantTablesHash(tables)]; ok {
+		return name
+	}
+	if _, exact := EstimateJpegQuality(tables); exact {
+		return JpegTablesIJG
+	}
+	return ""
+}
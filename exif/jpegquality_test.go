@@ -0,0 +1,101 @@
+package exif
+
+import (
+	"strings"
+	"testing"
+)
+
+// dqtSegment creates DQT segment with IJG luma and chroma tables of the given quality
+func dqtSegment(quality int) []byte {
+	data := make([]byte, 0, 130)
+	for id, standard := range []*[64]uint16{&standardLumaTable, &standardChromaTable} {
+		data = append(data, byte(id))
+		for i := 0; i < 64; i++ {
+			data = append(data, byte(ijgScale(standard[jpegZigzag[i]], quality)))
+		}
+	}
+	return jpegSegment(dqtMarker, data)
+}
+
+func TestReadingIjgQuality(t *testing.T) {
+	file, err := OpenExifFileIo(writeTempFile(t, ".jpg", buildJpegWithSegments(dqtSegment(85))))
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+
+	metadata, err := ReadMetadata(file)
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	tables := metadata.QuantizationTables
+	if len(tables) != 2 || tables[lumaTableID][0] != ijgScale(16, 85) || tables[lumaTableID][8] != ijgScale(12, 85) {
+		t.Fatalf("Invalid quantization tables: %v", tables)
+	}
+	if quality, exact := EstimateJpegQuality(tables); quality != 85 || !exact {
+		t.Fatalf("Invalid quality, expected: 85, actual: %d (exact %v)", quality, exact)
+	}
+	if IdentifyQuantTables(tables) != JpegTablesIJG {
+		t.Fatalf("IJG tables were not identified")
+	}
+}
+
+func TestEstimatingCustomTablesQuality(t *testing.T) {
+	luma := make([]uint16, 64)
+	chroma := make([]uint16, 64)
+	for i := range luma {
+		// twice the standard values is equivalent to IJG quality 25
+		luma[i] = standardLumaTable[i] * 2
+		chroma[i] = standardChromaTable[i] * 2
+	}
+	luma[0]++ // make tables non-IJG
+	quality, exact := EstimateJpegQuality(QuantizationTables{lumaTableID: luma, chromaTableID: chroma})
+	if quality != 25 || exact {
+		t.Fatalf("Invalid quality, expected: 25, actual: %d (exact %v)", quality, exact)
+	}
+	if name := IdentifyQuantTables(QuantizationTables{lumaTableID: luma, chromaTableID: chroma}); name != "" {
+		t.Fatalf("Unknown tables were identified as %s", name)
+	}
+}
+
+func TestReadingInvalidDqtSegment(t *testing.T) {
+	// segment size does not include even the size field
+	segment := []byte{0xFF, 0xDB, 0x00, 0x01}
+	file, err := OpenExifFileIo(writeTempFile(t, ".jpg", buildJpegWithSegments(segment)))
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+
+	_, err = ReadMetadata(file)
+	if err == nil || !strings.Contains(err.Error(), "DQT") {
+		t.Fatalf("Invalid DQT segment must be reported, got %v", err)
+	}
+}
+
+func TestIdentifyingQuantTables(t *testing.T) {
+	tests := []struct {
+		path   string
+		tables string
+	}{
+		{"../test-data/cameras/KonicaMinolta/DimageZ3.jpg", "ACD Systems"},
+		{"../test-data/cameras/Olympus/C760UZ.JPG", "ACD Systems"},
+		{"../test-data/scan/P1020297.JPG", "ACD Systems"},
+		{"../test-data/cameras/Panasonic/PanasonicGX1.jpeg", JpegTablesIJG},
+		{"../test-data/cameras/Fujifilm/Fuji-X-S10.jpg", ""},
+	}
+	for _, test := range tests {
+		file, err := OpenExifFileIo(test.path)
+		if err != nil {
+			t.Fatalf("Failed to open file %s: %v", test.path, err)
+		}
+		metadata, err := ReadMetadata(file)
+		file.Close()
+		if err != nil {
+			t.Fatalf("Failed to read metadata of %s: %v", test.path, err)
+		}
+		if tables := IdentifyQuantTables(metadata.QuantizationTables); tables != test.tables {
+			t.Errorf("Tables of %s identified as '%s', expected '%s'", test.path, tables, test.tables)
+		}
+	}
+}
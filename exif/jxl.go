@@ -0,0 +1,62 @@
+package exif
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+var jxlSignature = []byte{0, 0, 0, 0x0C, 'J', 'X', 'L', ' ', 0x0D, 0x0A, 0x87, 0x0A}
+
+// ErrCompressedExif is returned when the file contains Exif data only in compressed form, which cannot be read
+var ErrCompressedExif = errors.New("Exif data is compressed")
+
+func jxlDetector(header []byte) bool {
+	return bytes.HasPrefix(header, jxlSignature) || jxlCodestreamDetector(header)
+}
+
+func jxlCodestreamDetector(header []byte) bool {
+	return header[0] == 0xFF && header[1] == 0x0A
+}
+
+// readJxlTags reads tags from JPEG XL files. Exif data is stored in "Exif" box of the container, with 4-byte offset
+// to TIFF header before the TIFF structure. Metadata boxes can also be brotli-compressed and stored in "brob" boxes,
+// these are not supported
+func readJxlTags(file File) (Tags, error) {
+	header, err := file.readBytes(2)
+	if err != nil {
+		return nil, err
+	}
+	if jxlCodestreamDetector(header) {
+		// bare codestream does not contain any metadata
+		return make(Tags, 0), nil
+	}
+	boxes, err := readTopLevelBoxes(file)
+	if err != nil {
+		return nil, err
+	}
+	compressed := false
+	for _, box := range boxes {
+		switch box.Type {
+		case "Exif":
+			return readTiffBlockWithOffsetPrefix(file, box.Start)
+		case "brob":
+			_, err = file.seek(box.Start)
+			if err != nil {
+				return nil, err
+			}
+			boxType, err := file.readBytes(4)
+			if err != nil {
+				return nil, err
+			}
+			if string(boxType) == "Exif" {
+				compressed = true
+			}
+		}
+	}
+	if compressed {
+		return nil, fmt.Errorf("%w: brotli-compressed 'Exif' box in %s is not supported", ErrCompressedExif, file.GetPath())
+	}
+	// file does not contain exif
+	return make(Tags, 0), nil
+}
@@ -1,5 +1,13 @@
 package exif
 
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/uaraven/exif-stat/logger"
+)
+
 func nikonV3Detector(data []byte) bool {
 	header := []byte{'N', 'i', 'k', 'o', 'n', 0x00, 0x02, 0x10, 0x00, 0x00}
 	for i, v := range header {
@@ -42,21 +50,149 @@ func nikonV3Reader(file File, entry ifdEntry) (*ifd, error) {
 	return readIfd(file, -1, entry.IfdIndex)
 }
 
+// readRelativeIfd reads maker notes IFD which has its own byte order and which offsets are relative to the given base
+// instead of the main TIFF header. Entries with subIfdIDs point to nested IFDs, which are read with the same base.
+// Offsets and byte order of the main TIFF structure are restored afterwards
+func readRelativeIfd(file File, base int64, ifdOffset int64, order byte, ifdIndex int, subIfdIDs ...uint16) (*ifd, error) {
+	mainTiffHeaderOffset := file.GetTiffHeaderOffset()
+	mainOrder := file.GetOrder()
+	defer func() {
+		file.SetTiffHeaderOffset(mainTiffHeaderOffset)
+		file.SetOrder(mainOrder)
+	}()
+
+	_, err := file.seek(base + ifdOffset)
+	if err != nil {
+		return nil, err
+	}
+	file.SetTiffHeaderOffset(base)
+	file.SetOrder(order)
+	result, err := readIfd(file, -1, ifdIndex)
+	if err != nil || len(subIfdIDs) == 0 {
+		return result, err
+	}
+	for _, entry := range result.IfdEntries {
+		if !isSubIfdEntry(entry, subIfdIDs) {
+			continue
+		}
+		subIfd, err := readIfd(file, int64(entry.Data), ifdIndex)
+		if err != nil {
+			return nil, err
+		}
+		if result.SubIfds == nil {
+			result.SubIfds = make(map[uint16]*ifd)
+		}
+		result.SubIfds[entry.TagID] = subIfd
+	}
+	return result, nil
+}
+
+// sub-IFD is either referenced by offset or embedded as undefined value, data field contains offset in both cases
+func isSubIfdEntry(entry ifdEntry, subIfdIDs []uint16) bool {
+	if entry.DataType != TypeIfd && entry.DataType != TypeUnsignedLong && entry.DataType != TypeUndefined {
+		return false
+	}
+	for _, id := range subIfdIDs {
+		if entry.TagID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// guessIfdByteOrder detects byte order of the IFD which starts with the given data by its number of entries. Maker notes
+// never have more than 255 entries, so the order in which entry count is smaller is the right one
+func guessIfdByteOrder(data []byte, order byte) byte {
+	if len(data) < 2 {
+		return order
+	}
+	if binary.LittleEndian.Uint16(data) < binary.BigEndian.Uint16(data) {
+		return LittleEndian
+	} else if binary.BigEndian.Uint16(data) < binary.LittleEndian.Uint16(data) {
+		return BigEndian
+	}
+	return order
+}
+
 type makerNoteReader struct {
+	// Make is a prefix of the camera make, it is checked for the maker notes which do not have a header
+	Make    string
 	CanRead func([]byte) bool
 	Reader  func(File, ifdEntry) (*ifd, error)
+	// Decoder converts vendor-specific binary tags into named tags, it is optional
+	Decoder func(file File, parentIDs []uint16, tags Tags, camera cameraIdentity) Tags
+}
+
+func (reader makerNoteReader) matches(data []byte, camera cameraIdentity) bool {
+	if len(reader.Make) > 0 && !strings.HasPrefix(strings.ToUpper(camera.Make), strings.ToUpper(reader.Make)) {
+		return false
+	}
+	return reader.CanRead == nil || reader.CanRead(data)
 }
 
 var makerNoteReaders = []makerNoteReader{
-	{nikonV3Detector, nikonV3Reader},
-	{nikonV3VariantDetector, nikonV3Reader},
+	{CanRead: nikonV3Detector, Reader: nikonV3Reader, Decoder: decodeNikonMakerNote},
+	{CanRead: nikonV3VariantDetector, Reader: nikonV3Reader, Decoder: decodeNikonMakerNote},
+	{Make: "Canon", Reader: canonReader, Decoder: decodeCanonMakerNote},
+	{CanRead: sonyDetector, Reader: sonyReader, Decoder: decodeSonyMakerNote},
+	{CanRead: fujiDetector, Reader: fujiReader},
+	{CanRead: olympusDetector, Reader: olympusReader},
+	{CanRead: panasonicDetector, Reader: panasonicReader},
+	{CanRead: pentaxDetector, Reader: pentaxReader, Decoder: decodePentaxMakerNote},
+	{CanRead: appleDetector, Reader: appleReader},
 }
 
-func readMakerNotes(file File, entry ifdEntry) (*ifd, error) {
+// readMakerNoteTags reads maker notes with the first matching reader and converts them into tags. Unknown maker notes
+// produce no tags. Maker notes which cannot be read are skipped, so that they never spoil the rest of Exif
+func readMakerNoteTags(parentIDs []uint16, file File, entry ifdEntry, camera cameraIdentity) (Tags, error) {
 	for _, reader := range makerNoteReaders {
-		if reader.CanRead(entry.ValueBytes) {
-			return reader.Reader(file, entry)
+		if !reader.matches(entry.ValueBytes, camera) {
+			continue
+		}
+		makerNotes, err := reader.Reader(file, entry)
+		if err != nil {
+			logger.Verbose(2, fmt.Sprintf("Invalid maker notes in %s: %v", file.GetPath(), err))
+			return nil, nil
+		}
+		if makerNotes == nil {
+			return nil, nil
+		}
+		tags, err := makerNoteIfdToTags(parentIDs, file, makerNotes, camera)
+		if err != nil {
+			logger.Verbose(2, fmt.Sprintf("Invalid maker notes in %s: %v", file.GetPath(), err))
+			return nil, nil
 		}
+		if reader.Decoder != nil {
+			tags = reader.Decoder(file, parentIDs, tags, camera)
+		}
+		return tags, nil
 	}
 	return nil, nil
 }
+
+// makerNoteIfdToTags converts maker notes IFD into tags. Tags of the nested IFDs have ID of the pointing entry in their
+// path, i.e. lens model from Olympus equipment IFD has path "8769/927c/2010/0203"
+func makerNoteIfdToTags(parentIDs []uint16, file File, makerNotes *ifd, camera cameraIdentity) (Tags, error) {
+	entries := make([]ifdEntry, 0, len(makerNotes.IfdEntries))
+	for _, entry := range makerNotes.IfdEntries {
+		if _, ok := makerNotes.SubIfds[entry.TagID]; !ok {
+			entries = append(entries, entry)
+		}
+	}
+	tags, err := entriesToTags(parentIDs, file, entries, camera)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range makerNotes.IfdEntries {
+		subIfd, ok := makerNotes.SubIfds[entry.TagID]
+		if !ok {
+			continue
+		}
+		subIfdTags, err := entriesToTags(childPath(parentIDs, entry.TagID), file, subIfd.IfdEntries, camera)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, subIfdTags...)
+	}
+	return tags, nil
+}
@@ -0,0 +1,23 @@
+package exif
+
+// Metadata contains Exif tags and all the other metadata found in the file
+type Metadata struct {
+	// name of the detected container format, i.e. "JPEG" or FormatVideo
+	Format string
+	Tags   Tags
+	// XMP properties from XMP packets embedded into the file
+	Xmp XmpProperties
+	// IPTC datasets from Photoshop image resources
+	Iptc IptcProperties
+	// images listed in Multi-Picture Format index, including the primary image
+	Images []MpImage
+	// JPEG frame header of the main image
+	Frame *JpegFrame
+	// JPEG quantization tables of the main image
+	QuantizationTables QuantizationTables
+	// ICC colour profile
+	IccProfile *IccProfile
+
+	// ICC profile chunks from APP2 segments, indexed by the sequence number
+	iccChunks [][]byte
+}
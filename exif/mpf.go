@@ -0,0 +1,121 @@
+package exif
+
+import (
+	"fmt"
+)
+
+const (
+	mpfIdentifier = "MPF"
+	mpEntryTagID  = 0xB002
+	mpEntrySize   = 16
+)
+
+// MP image types. The highest byte of the type is a class of the image, lower bytes are the type within the class
+const (
+	MpTypeUndefined            uint32 = 0x000000
+	MpTypeLargeThumbnailVga    uint32 = 0x010001
+	MpTypeLargeThumbnailFullHd uint32 = 0x010002
+	MpTypePanorama             uint32 = 0x020001
+	MpTypeDisparity            uint32 = 0x020002
+	MpTypeMultiAngle           uint32 = 0x020003
+	MpTypeBaselinePrimary      uint32 = 0x030000
+
+	mpTypeMask            = 0x00FFFFFF
+	mpClassLargeThumbnail = 0x01
+)
+
+var mpTypeNames = map[uint32]string{
+	MpTypeUndefined:            "Undefined",
+	MpTypeLargeThumbnailVga:    "Large Thumbnail (VGA)",
+	MpTypeLargeThumbnailFullHd: "Large Thumbnail (Full HD)",
+	MpTypePanorama:             "Multi-frame Panorama",
+	MpTypeDisparity:            "Multi-frame Disparity",
+	MpTypeMultiAngle:           "Multi-frame Multi-angle",
+	MpTypeBaselinePrimary:      "Baseline MP Primary Image",
+}
+
+// MpImage is an image listed in the Multi-Picture Format index
+type MpImage struct {
+	// MP image type, without attribute flags
+	Type uint32
+	// Size of the image in bytes
+	Size uint32
+	// Absolute offset of the image in the file
+	Offset int64
+}
+
+// TypeName returns the human-readable name of the image type
+func (image MpImage) TypeName() string {
+	if name, ok := mpTypeNames[image.Type]; ok {
+		return name
+	}
+	return fmt.Sprintf("Unknown (%06x)", image.Type)
+}
+
+// IsPreview returns true for the images which are just the large previews of the primary image
+func (image MpImage) IsPreview() bool {
+	return image.Type>>16 == mpClassLargeThumbnail
+}
+
+// readMpfSegment reads MP Index IFD from APP2 segment. MP Index IFD is a TIFF structure, offsets of the images are
+// relative to its header. Only the first image of MP file contains the index
+func readMpfSegment(file File, marker *marker, metadata *Metadata) error {
+	mainTiffHeaderOffset := file.GetTiffHeaderOffset()
+	mainOrder := file.GetOrder()
+	defer func() {
+		file.SetTiffHeaderOffset(mainTiffHeaderOffset)
+		file.SetOrder(mainOrder)
+	}()
+
+	mpHeaderOffset := marker.Offset + int64(len(mpfIdentifier)) + 1
+	_, err := file.seek(mpHeaderOffset)
+	if err != nil {
+		return err
+	}
+	file.SetTiffHeaderOffset(mpHeaderOffset)
+	err = readTiffHeader(file)
+	if err != nil {
+		return err
+	}
+	mpIfd, err := readIfd(file, -1, 0)
+	if err != nil {
+		return err
+	}
+	var entries []byte
+	for _, entry := range mpIfd.IfdEntries {
+		if entry.TagID == mpEntryTagID {
+			entries = entry.ValueBytes
+		}
+	}
+	order := file.getByteOrder()
+	for pos := 0; pos+mpEntrySize <= len(entries); pos += mpEntrySize {
+		image := MpImage{
+			Type: order.Uint32(entries[pos:pos+4]) & mpTypeMask,
+			Size: order.Uint32(entries[pos+4 : pos+8]),
+		}
+		// offset of the first image is always zero, it is the image which contains the index, and its offset is set
+		// when the whole JPEG is read
+		if offset := order.Uint32(entries[pos+8 : pos+12]); offset != 0 {
+			image.Offset = mpHeaderOffset + int64(offset)
+		}
+		metadata.Images = append(metadata.Images, image)
+	}
+	return nil
+}
+
+// ReadMpImageMetadata reads metadata of the image embedded into Multi-Picture Format file
+func ReadMpImageMetadata(file File, image MpImage) (*Metadata, error) {
+	file.SetOrder(BigEndian)
+	_, err := file.seek(image.Offset)
+	if err != nil {
+		return nil, err
+	}
+	soi, err := file.readUint16()
+	if err != nil {
+		return nil, err
+	}
+	if soi != soiDataMarker {
+		return nil, fmt.Errorf("MP image at %d is not a JPEG image", image.Offset)
+	}
+	return readJpegMetadataAt(file, image.Offset)
+}
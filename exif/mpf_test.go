@@ -0,0 +1,81 @@
+package exif
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func mpEntry(imageType uint32, size uint32, offset uint32) []byte {
+	data := make([]byte, mpEntrySize)
+	binary.BigEndian.PutUint32(data[0:], imageType)
+	binary.BigEndian.PutUint32(data[4:], size)
+	binary.BigEndian.PutUint32(data[8:], offset)
+	return data
+}
+
+// buildMpo creates MP file with a primary image followed by a secondary image of given type
+func buildMpo(secondaryType uint32) ([]byte, int) {
+	exifSegment := func(model string) []byte {
+		tiff := buildTiff([]tiffEntry{asciiEntry(0x010f, "TestMake"), asciiEntry(0x0110, model)})
+		return jpegSegment(exifDataMarker, append([]byte{'E', 'x', 'i', 'f', 0, 0}, tiff...))
+	}
+	secondary := buildJpeg(buildTiff([]tiffEntry{asciiEntry(0x010f, "TestMake"), asciiEntry(0x0110, "Right")}))
+	mpfSegment := func(primarySize uint32, secondaryOffset uint32) []byte {
+		entries := append(mpEntry(0x20000000|MpTypeBaselinePrimary, primarySize, 0),
+			mpEntry(secondaryType, uint32(len(secondary)), secondaryOffset)...)
+		mpf := buildTiff([]tiffEntry{
+			undefinedEntry(0xB000, []byte("0100")),
+			longEntry(0xB001, 2),
+			undefinedEntry(mpEntryTagID, entries),
+		})
+		return jpegSegment(app2Marker, append([]byte(mpfIdentifier+"\x00"), mpf...))
+	}
+	exif := exifSegment("Left")
+	// size of the primary image does not depend on the values in MP entries
+	primarySize := len(buildJpegWithSegments(exif, mpfSegment(0, 0)))
+	mpHeaderOffset := 2 + len(exif) + 4 + len(mpfIdentifier) + 1
+	primary := buildJpegWithSegments(exif, mpfSegment(uint32(primarySize), uint32(primarySize-mpHeaderOffset)))
+	return append(primary, secondary...), primarySize
+}
+
+func TestReadingMpf(t *testing.T) {
+	mpo, primarySize := buildMpo(MpTypeDisparity)
+	file, err := OpenExifFileIo(writeTempFile(t, ".mpo", mpo))
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+
+	metadata, err := ReadMetadata(file)
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	if tag, ok := TagsAsMap(metadata.Tags)[model]; !ok || tag.Value.(string) != "Left" {
+		t.Fatalf("Invalid primary image Exif: %v", tag.Value)
+	}
+	if len(metadata.Images) != 2 {
+		t.Fatalf("Expected 2 MP images, found %d", len(metadata.Images))
+	}
+	primary, secondary := metadata.Images[0], metadata.Images[1]
+	if primary.Type != MpTypeBaselinePrimary || primary.Offset != 0 || primary.Size != uint32(primarySize) {
+		t.Fatalf("Invalid primary image: %v", primary)
+	}
+	if secondary.Type != MpTypeDisparity || secondary.Offset != int64(primarySize) || secondary.IsPreview() {
+		t.Fatalf("Invalid secondary image: %v", secondary)
+	}
+
+	secondaryMetadata, err := ReadMpImageMetadata(file, secondary)
+	if err != nil {
+		t.Fatalf("Failed to read secondary image metadata: %v", err)
+	}
+	if tag, ok := TagsAsMap(secondaryMetadata.Tags)[model]; !ok || tag.Value.(string) != "Right" {
+		t.Fatalf("Invalid secondary image Exif: %v", tag.Value)
+	}
+}
+
+func TestMpPreviewType(t *testing.T) {
+	image := MpImage{Type: MpTypeLargeThumbnailFullHd}
+	if !image.IsPreview() || image.TypeName() != "Large Thumbnail (Full HD)" {
+		t.Fatalf("Invalid preview image type: %s", image.TypeName())
+	}
+}
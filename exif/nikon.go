@@ -0,0 +1,194 @@
+package exif
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Nikon maker notes tags
+const (
+	NikonQualityTagID      = 0x0004
+	NikonSerialNumberTagID = 0x001d
+	NikonLensTypeTagID     = 0x0083
+	NikonLensTagID         = 0x0084
+	NikonShotInfoTagID     = 0x0091
+	NikonLensDataTagID     = 0x0098
+	NikonShutterCountTagID = 0x00a7
+)
+
+// values of lens data which form composite lens ID, together with the lens type. Values are exposed as tags with their
+// index as tag ID, i.e. lens ID number has path "8769/927c/0098/0000"
+var nikonLensDataNames = []string{
+	"LensIDNumber",
+	"LensFStops",
+	"MinFocalLength",
+	"MaxFocalLength",
+	"MaxApertureAtMinFocal",
+	"MaxApertureAtMaxFocal",
+	"MCUVersion",
+}
+
+// offsets of the lens ID number in lens data of different versions, the rest of the values follow it. Layout of
+// lens data of Z-mount cameras (version 0400 and later) is different and is not decoded
+var nikonLensIDOffsets = map[string]int{
+	"0100": 0x06,
+	"0101": 0x0b,
+	"0201": 0x0b,
+	"0202": 0x0b,
+	"0203": 0x0b,
+	"0204": 0x0c,
+}
+
+// the data after 4 bytes of version is encrypted in lens data and shot info of version 0200 and later
+const nikonEncryptedDataStart = 4
+
+var nikonEncryptedVersion = regexp.MustCompile(`^0[2-9]\d\d`)
+
+// Nikon decryption tables
+var nikonXlat = [2][256]byte{
+	{
+		0xc1, 0xbf, 0x6d, 0x0d, 0x59, 0xc5, 0x13, 0x9d, 0x83, 0x61, 0x6b, 0x4f, 0xc7, 0x7f, 0x3d, 0x3d,
+		0x53, 0x59, 0xe3, 0xc7, 0xe9, 0x2f, 0x95, 0xa7, 0x95, 0x1f, 0xdf, 0x7f, 0x2b, 0x29, 0xc7, 0x0d,
+		0xdf, 0x07, 0xef, 0x71, 0x89, 0x3d, 0x13, 0x3d, 0x3b, 0x13, 0xfb, 0x0d, 0x89, 0xc1, 0x65, 0x1f,
+		0xb3, 0x0d, 0x6b, 0x29, 0xe3, 0xfb, 0xef, 0xa3, 0x6b, 0x47, 0x7f, 0x95, 0x35, 0xa7, 0x47, 0x4f,
+		0xc7, 0xf1, 0x59, 0x95, 0x35, 0x11, 0x29, 0x61, 0xf1, 0x3d, 0xb3, 0x2b, 0x0d, 0x43, 0x89, 0xc1,
+		0x9d, 0x9d, 0x89, 0x65, 0xf1, 0xe9, 0xdf, 0xbf, 0x3d, 0x7f, 0x53, 0x97, 0xe5, 0xe9, 0x95, 0x17,
+		0x1d, 0x3d, 0x8b, 0xfb, 0xc7, 0xe3, 0x67, 0xa7, 0x07, 0xf1, 0x71, 0xa7, 0x53, 0xb5, 0x29, 0x89,
+		0xe5, 0x2b, 0xa7, 0x17, 0x29, 0xe9, 0x4f, 0xc5, 0x65, 0x6d, 0x6b, 0xef, 0x0d, 0x89, 0x49, 0x2f,
+		0xb3, 0x43, 0x53, 0x65, 0x1d, 0x49, 0xa3, 0x13, 0x89, 0x59, 0xef, 0x6b, 0xef, 0x65, 0x1d, 0x0b,
+		0x59, 0x13, 0xe3, 0x4f, 0x9d, 0xb3, 0x29, 0x43, 0x2b, 0x07, 0x1d, 0x95, 0x59, 0x59, 0x47, 0xfb,
+		0xe5, 0xe9, 0x61, 0x47, 0x2f, 0x35, 0x7f, 0x17, 0x7f, 0xef, 0x7f, 0x95, 0x95, 0x71, 0xd3, 0xa3,
+		0x0b, 0x71, 0xa3, 0xad, 0x0b, 0x3b, 0xb5, 0xfb, 0xa3, 0xbf, 0x4f, 0x83, 0x1d, 0xad, 0xe9, 0x2f,
+		0x71, 0x65, 0xa3, 0xe5, 0x07, 0x35, 0x3d, 0x0d, 0xb5, 0xe9, 0xe5, 0x47, 0x3b, 0x9d, 0xef, 0x35,
+		0xa3, 0xbf, 0xb3, 0xdf, 0x53, 0xd3, 0x97, 0x53, 0x49, 0x71, 0x07, 0x35, 0x61, 0x71, 0x2f, 0x43,
+		0x2f, 0x11, 0xdf, 0x17, 0x97, 0xfb, 0x95, 0x3b, 0x7f, 0x6b, 0xd3, 0x25, 0xbf, 0xad, 0xc7, 0xc5,
+		0xc5, 0xb5, 0x8b, 0xef, 0x2f, 0xd3, 0x07, 0x6b, 0x25, 0x49, 0x95, 0x25, 0x49, 0x6d, 0x71, 0xc7,
+	},
+	{
+		0xa7, 0xbc, 0xc9, 0xad, 0x91, 0xdf, 0x85, 0xe5, 0xd4, 0x78, 0xd5, 0x17, 0x46, 0x7c, 0x29, 0x4c,
+		0x4d, 0x03, 0xe9, 0x25, 0x68, 0x11, 0x86, 0xb3, 0xbd, 0xf7, 0x6f, 0x61, 0x22, 0xa2, 0x26, 0x34,
+		0x2a, 0xbe, 0x1e, 0x46, 0x14, 0x68, 0x9d, 0x44, 0x18, 0xc2, 0x40, 0xf4, 0x7e, 0x5f, 0x1b, 0xad,
+		0x0b, 0x94, 0xb6, 0x67, 0xb4, 0x0b, 0xe1, 0xea, 0x95, 0x9c, 0x66, 0xdc, 0xe7, 0x5d, 0x6c, 0x05,
+		0xda, 0xd5, 0xdf, 0x7a, 0xef, 0xf6, 0xdb, 0x1f, 0x82, 0x4c, 0xc0, 0x68, 0x47, 0xa1, 0xbd, 0xee,
+		0x39, 0x50, 0x56, 0x4a, 0xdd, 0xdf, 0xa5, 0xf8, 0xc6, 0xda, 0xca, 0x90, 0xca, 0x01, 0x42, 0x9d,
+		0x8b, 0x0c, 0x73, 0x43, 0x75, 0x05, 0x94, 0xde, 0x24, 0xb3, 0x80, 0x34, 0xe5, 0x2c, 0xdc, 0x9b,
+		0x3f, 0xca, 0x33, 0x45, 0xd0, 0xdb, 0x5f, 0xf5, 0x52, 0xc3, 0x21, 0xda, 0xe2, 0x22, 0x72, 0x6b,
+		0x3e, 0xd0, 0x5b, 0xa8, 0x87, 0x8c, 0x06, 0x5d, 0x0f, 0xdd, 0x09, 0x19, 0x93, 0xd0, 0xb9, 0xfc,
+		0x8b, 0x0f, 0x84, 0x60, 0x33, 0x1c, 0x9b, 0x45, 0xf1, 0xf0, 0xa3, 0x94, 0x3a, 0x12, 0x77, 0x33,
+		0x4d, 0x44, 0x78, 0x28, 0x3c, 0x9e, 0xfd, 0x65, 0x57, 0x16, 0x94, 0x6b, 0xfb, 0x59, 0xd0, 0xc8,
+		0x22, 0x36, 0xdb, 0xd2, 0x63, 0x98, 0x43, 0xa1, 0x04, 0x87, 0x86, 0xf7, 0xa6, 0x26, 0xbb, 0xd6,
+		0x59, 0x4d, 0xbf, 0x6a, 0x2e, 0xaa, 0x2b, 0xef, 0xe6, 0x78, 0xb6, 0x4e, 0xe0, 0x2f, 0xdc, 0x7c,
+		0xbe, 0x57, 0x19, 0x32, 0x7e, 0x2a, 0xd0, 0xb8, 0xba, 0x29, 0x00, 0x3c, 0x52, 0x7d, 0xa8, 0x49,
+		0x3b, 0x2d, 0xeb, 0x25, 0x49, 0xfa, 0xa3, 0xaa, 0x39, 0xa7, 0xc5, 0xa7, 0x50, 0x11, 0x36, 0xfb,
+		0xc6, 0x67, 0x4a, 0xf5, 0xa5, 0x12, 0x65, 0x7e, 0xb0, 0xdf, 0xaf, 0x4e, 0xb3, 0x61, 0x7f, 0x2f,
+	},
+}
+
+func init() {
+	for index, name := range nikonLensDataNames {
+		tagNames[fmt.Sprintf("%04x/%04x/%04x/%04x", exifTagID, makerNotesTagID, NikonLensDataTagID, index)] = "Nikon " + name
+	}
+	tagNames[fmt.Sprintf("%04x/%04x/%04x", exifTagID, makerNotesTagID, NikonQualityTagID)] = "Nikon Quality"
+	tagNames[fmt.Sprintf("%04x/%04x/%04x", exifTagID, makerNotesTagID, NikonSerialNumberTagID)] = "Nikon Serial Number"
+	tagNames[fmt.Sprintf("%04x/%04x/%04x", exifTagID, makerNotesTagID, NikonLensTypeTagID)] = "Nikon Lens Type"
+	tagNames[fmt.Sprintf("%04x/%04x/%04x", exifTagID, makerNotesTagID, NikonShutterCountTagID)] = "Nikon Shutter Count"
+}
+
+var nikonD50Model = regexp.MustCompile(`\bD50$`)
+
+// nikonSerialKey returns the first decryption key. Numeric serial number is the key, the cameras which have
+// non-numeric serial number use the constant key
+func nikonSerialKey(serial string, camera cameraIdentity) byte {
+	if value, err := strconv.ParseUint(serial, 10, 64); err == nil {
+		return byte(value)
+	}
+	if nikonD50Model.MatchString(camera.Model) {
+		return 0x22
+	}
+	return 0x60
+}
+
+// nikonDecrypt returns decrypted copy of the data. Serial number and shutter count are the keys
+func nikonDecrypt(data []byte, serialKey byte, shutterCount uint32) []byte {
+	countKey := byte(shutterCount) ^ byte(shutterCount>>8) ^ byte(shutterCount>>16) ^ byte(shutterCount>>24)
+	ci := nikonXlat[0][serialKey]
+	cj := nikonXlat[1][countKey]
+	ck := byte(0x60)
+	result := make([]byte, len(data))
+	for i, b := range data {
+		cj += ci * ck
+		ck++
+		result[i] = b ^ cj
+	}
+	return result
+}
+
+// decryptNikonBlock decrypts lens data or shot info, first 4 bytes of the block are version and are never encrypted
+func decryptNikonBlock(data []byte, serialKey byte, shutterCount uint32) []byte {
+	if len(data) <= nikonEncryptedDataStart || !nikonEncryptedVersion.Match(data[:nikonEncryptedDataStart]) {
+		return data
+	}
+	result := make([]byte, 0, len(data))
+	result = append(result, data[:nikonEncryptedDataStart]...)
+	return append(result, nikonDecrypt(data[nikonEncryptedDataStart:], serialKey, shutterCount)...)
+}
+
+// nikonLensDataTags returns the values of lens data which form composite lens ID
+func nikonLensDataTags(parentIDs []uint16, data []byte) Tags {
+	if len(data) < 4 {
+		return nil
+	}
+	offset, ok := nikonLensIDOffsets[string(data[:4])]
+	if !ok || offset+len(nikonLensDataNames) > len(data) {
+		return nil
+	}
+	tags := make(Tags, 0, len(nikonLensDataNames))
+	for index := range nikonLensDataNames {
+		tags = append(tags, Tag{
+			ID:       uint16(index),
+			IDPath:   parentIDs,
+			DataType: TypeUnsignedByte,
+			Value:    []byte{data[offset+index]},
+			RawData:  []byte{data[offset+index]},
+		})
+	}
+	return tags
+}
+
+// decodeNikonMakerNote decrypts lens data and shot info blocks and adds tags for the values of lens data. Encryption
+// keys are serial number and shutter count, blocks are left intact when the keys are missing
+func decodeNikonMakerNote(file File, parentIDs []uint16, tags Tags, camera cameraIdentity) Tags {
+	var serial string
+	var shutterCount uint32
+	hasShutterCount := false
+	for _, tag := range tags {
+		switch tag.ID {
+		case NikonSerialNumberTagID:
+			serial, _ = tag.Value.(string)
+		case NikonShutterCountTagID:
+			if value, ok := tag.Value.([]uint32); ok && len(value) > 0 {
+				shutterCount = value[0]
+				hasShutterCount = true
+			}
+		}
+	}
+	serialKey := nikonSerialKey(serial, camera)
+	result := make(Tags, 0, len(tags))
+	for _, tag := range tags {
+		data, ok := tag.Value.([]byte)
+		if !ok || (tag.ID != NikonLensDataTagID && tag.ID != NikonShotInfoTagID) {
+			result = append(result, tag)
+			continue
+		}
+		if hasShutterCount {
+			tag.Value = decryptNikonBlock(data, serialKey, shutterCount)
+			tag.RawData = tag.Value.([]byte)
+		}
+		result = append(result, tag)
+		if tag.ID == NikonLensDataTagID && (hasShutterCount || !nikonEncryptedVersion.Match(data)) {
+			result = append(result, nikonLensDataTags(childPath(parentIDs, tag.ID), tag.RawData)...)
+		}
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package exif
+
+import (
+	"testing"
+)
+
+func TestReadingNikonEncryptedLensData(t *testing.T) {
+	lensData := append([]byte("0204"), make([]byte, 29)...)
+	copy(lensData[0x0c:], []byte{0x8D, 0x44, 0x5C, 0x8E, 0x34, 0x3C, 0x8F})
+	// encryption is symmetric
+	encrypted := decryptNikonBlock(lensData, nikonSerialKey("3012345", cameraIdentity{}), 4567)
+	if string(encrypted[4:]) == string(lensData[4:]) {
+		t.Fatalf("Lens data must be encrypted")
+	}
+	makerNote := append([]byte("Nikon\x00\x02\x10\x00\x00"), buildTiff([]tiffEntry{
+		asciiEntry(NikonSerialNumberTagID, "3012345"),
+		byteEntry(NikonLensTypeTagID, 0x0E),
+		undefinedEntry(NikonLensDataTagID, encrypted),
+		longEntry(NikonShutterCountTagID, 4567),
+	})...)
+	tiff := buildTiff([]tiffEntry{
+		asciiEntry(0x010f, "NIKON CORPORATION"),
+		asciiEntry(0x0110, "NIKON D7000"),
+		{ID: exifTagID, Sub: []tiffEntry{
+			undefinedEntry(makerNotesTagID, makerNote),
+		}},
+	})
+	tagMap := readTestTags(t, writeTempFile(t, ".jpg", buildJpeg(tiff)))
+
+	if tag, ok := tagMap["8769/927c/0098"]; !ok || string(tag.Value.([]byte)) != string(lensData) {
+		t.Fatalf("Failed to decrypt Nikon lens data")
+	}
+	if tag, ok := tagMap["8769/927c/0098/0000"]; !ok || tag.Value.([]byte)[0] != 0x8D || tag.Name() != "Nikon LensIDNumber" {
+		t.Fatalf("Failed to read Nikon lens ID number")
+	}
+	if tag, ok := tagMap["8769/927c/0098/0006"]; !ok || tag.Value.([]byte)[0] != 0x8F {
+		t.Fatalf("Failed to read Nikon MCU version")
+	}
+	if tag, ok := tagMap["8769/927c/00a7"]; !ok || tag.Value.([]uint32)[0] != 4567 {
+		t.Fatalf("Failed to read Nikon shutter count")
+	}
+}
+
+func TestNikonSerialKey(t *testing.T) {
+	if key := nikonSerialKey("3012345", cameraIdentity{}); key != byte(3012345&0xff) {
+		t.Fatalf("Invalid key of numeric serial number: %x", key)
+	}
+	if key := nikonSerialKey("", cameraIdentity{Model: "NIKON D50"}); key != 0x22 {
+		t.Fatalf("Invalid key of D50: %x", key)
+	}
+	if key := nikonSerialKey("", cameraIdentity{Model: "NIKON D200"}); key != 0x60 {
+		t.Fatalf("Invalid default key: %x", key)
+	}
+}
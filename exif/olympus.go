@@ -0,0 +1,107 @@
+package exif
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Olympus maker notes tags
+const (
+	OlympusEquipmentTagID       = 0x2010
+	OlympusCameraSettingsTagID  = 0x2020
+	OlympusRawDevelopmentTagID  = 0x2030
+	OlympusImageProcessingTagID = 0x2040
+	OlympusFocusInfoTagID       = 0x2050
+
+	// tags of equipment IFD
+	OlympusBodySerialNumberTagID = 0x0101
+	OlympusLensTypeTagID         = 0x0201
+	OlympusLensSerialNumberTagID = 0x0202
+	OlympusLensModelTagID        = 0x0203
+	// tags of camera settings IFD
+	OlympusFocusModeTagID = 0x0301
+	// tags of focus info IFD
+	OlympusFocusDistanceTagID = 0x0305
+)
+
+// Olympus maker notes come in three flavours:
+//   - "OLYMP\0" of the older cameras, IFD follows 8 bytes header, offsets are relative to the main TIFF header;
+//   - "OLYMPUS\0II" of the cameras since E-3, IFD follows 12 bytes header, offsets are relative to the maker notes;
+//   - "OM SYSTEM\0\0\0II" of OM System cameras, IFD follows 16 bytes header, offsets are relative to the maker notes
+var (
+	olympusOldHeader = []byte("OLYMP\x00")
+	olympusHeader    = []byte("OLYMPUS\x00")
+	omSystemHeader   = []byte("OM SYSTEM\x00\x00\x00")
+)
+
+var olympusSubIfdIDs = []uint16{
+	OlympusEquipmentTagID,
+	OlympusCameraSettingsTagID,
+	OlympusRawDevelopmentTagID,
+	OlympusImageProcessingTagID,
+	OlympusFocusInfoTagID,
+}
+
+var olympusTagNames = map[uint16]map[uint16]string{
+	OlympusEquipmentTagID: {
+		0x0100:                       "CameraType",
+		OlympusBodySerialNumberTagID: "SerialNumber",
+		OlympusLensTypeTagID:         "LensType",
+		OlympusLensSerialNumberTagID: "LensSerialNumber",
+		OlympusLensModelTagID:        "LensModel",
+		0x0204:                       "LensFirmwareVersion",
+		0x0301:                       "Extender",
+		0x1000:                       "FlashType",
+	},
+	OlympusCameraSettingsTagID: {
+		0x0200:                "ExposureMode",
+		0x0202:                "MeteringMode",
+		OlympusFocusModeTagID: "FocusMode",
+		0x0302:                "FocusProcess",
+		0x0400:                "FlashMode",
+		0x0500:                "WhiteBalance2",
+		0x0520:                "PictureMode",
+		0x0600:                "DriveMode",
+	},
+	OlympusFocusInfoTagID: {
+		0x0209:                    "AutoFocus",
+		OlympusFocusDistanceTagID: "FocusDistance",
+		0x1600:                    "ImageStabilization",
+	},
+}
+
+func init() {
+	for ifdID, names := range olympusTagNames {
+		for id, name := range names {
+			tagNames[fmt.Sprintf("%04x/%04x/%04x/%04x", exifTagID, makerNotesTagID, ifdID, id)] = "Olympus " + name
+		}
+	}
+}
+
+func olympusDetector(data []byte) bool {
+	return bytes.HasPrefix(data, olympusOldHeader) || bytes.HasPrefix(data, olympusHeader) ||
+		bytes.HasPrefix(data, omSystemHeader)
+}
+
+func olympusReader(file File, entry ifdEntry) (*ifd, error) {
+	makerNoteOffset := file.GetTiffHeaderOffset() + int64(entry.Data)
+	var header []byte
+	switch {
+	case bytes.HasPrefix(entry.ValueBytes, olympusHeader):
+		header = olympusHeader
+	case bytes.HasPrefix(entry.ValueBytes, omSystemHeader):
+		header = omSystemHeader
+	default:
+		return readRelativeIfd(file, file.GetTiffHeaderOffset(), int64(entry.Data)+8, file.GetOrder(), entry.IfdIndex,
+			olympusSubIfdIDs...)
+	}
+	// byte order mark and version follow the header
+	if len(entry.ValueBytes) < len(header)+4 {
+		return nil, nil
+	}
+	order := entry.ValueBytes[len(header)]
+	if order != LittleEndian && order != BigEndian {
+		return nil, fmt.Errorf("Invalid byte order of Olympus maker notes: %x", order)
+	}
+	return readRelativeIfd(file, makerNoteOffset, int64(len(header)+4), order, entry.IfdIndex, olympusSubIfdIDs...)
+}
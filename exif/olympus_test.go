@@ -0,0 +1,55 @@
+package exif
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestReadingOlympusMakerNotes(t *testing.T) {
+	makerNote := buildMakerNote(binary.BigEndian, []byte("OLYMPUS\x00MM\x03\x00"), []tiffEntry{
+		longEntry(0x0000, 0x30313030),
+		{ID: OlympusEquipmentTagID, Type: TypeUndefined, Sub: []tiffEntry{
+			asciiEntry(OlympusBodySerialNumberTagID, "BHP123456"),
+			asciiEntry(OlympusLensSerialNumberTagID, "ABF987654"),
+			asciiEntry(OlympusLensModelTagID, "OLYMPUS M.12-40mm F2.8"),
+		}},
+		{ID: OlympusFocusInfoTagID, Type: TypeUndefined, Sub: []tiffEntry{
+			rationalEntry(OlympusFocusDistanceTagID, 1250, 10),
+		}},
+	})
+	tiff := buildTiffWithOrder(binary.LittleEndian, []tiffEntry{
+		asciiEntry(0x010f, "OLYMPUS CORPORATION"),
+		{ID: exifTagID, Sub: []tiffEntry{
+			undefinedEntry(makerNotesTagID, makerNote),
+			asciiEntry(0x9003, "2021:05:01 12:34:56"),
+		}},
+	})
+	tagMap := readTestTags(t, writeTempFile(t, ".jpg", buildJpeg(tiff)))
+
+	if tag, ok := tagMap["8769/927c/2010/0203"]; !ok || tag.Value.(string) != "OLYMPUS M.12-40mm F2.8" || tag.Name() != "Olympus LensModel" {
+		t.Fatalf("Failed to read Olympus lens model")
+	}
+	if tag, ok := tagMap["8769/927c/2010/0101"]; !ok || tag.Value.(string) != "BHP123456" {
+		t.Fatalf("Failed to read Olympus body serial number")
+	}
+	if tag, ok := tagMap["8769/927c/2010/0202"]; !ok || tag.Value.(string) != "ABF987654" {
+		t.Fatalf("Failed to read Olympus lens serial number")
+	}
+	if tag, ok := tagMap["8769/927c/2050/0305"]; !ok || tag.Value.([]Rational)[0].Numerator != 1250 {
+		t.Fatalf("Failed to read Olympus focus distance")
+	}
+	if _, ok := tagMap["8769/927c/2010"]; ok {
+		t.Fatalf("Olympus sub-IFD pointer must not be reported as a tag")
+	}
+	if tag, ok := tagMap["8769/9003"]; !ok || tag.Value.(string) != "2021:05:01 12:34:56" {
+		t.Fatalf("Failed to read Exif after Olympus maker notes")
+	}
+}
+
+func TestReadingOldOlympusMakerNotes(t *testing.T) {
+	tagMap := readTestTags(t, "../test-data/cameras/Olympus/C760UZ.JPG")
+
+	if _, ok := tagMap["8769/927c/0200"]; !ok {
+		t.Fatalf("Failed to read Olympus maker notes")
+	}
+}
@@ -0,0 +1,54 @@
+package exif
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Panasonic maker notes tags
+const (
+	PanasonicImageQualityTagID         = 0x0001
+	PanasonicShootingModeTagID         = 0x001f
+	PanasonicInternalSerialNumberTagID = 0x0025
+	PanasonicBurstModeTagID            = 0x002a
+	PanasonicProgramIsoTagID           = 0x003c
+	PanasonicLensTypeTagID             = 0x0051
+	PanasonicLensSerialNumberTagID     = 0x0052
+	PanasonicAccessoryTypeTagID        = 0x0053
+	PanasonicSceneModeTagID            = 0x8001
+)
+
+var panasonicHeader = []byte("Panasonic\x00\x00\x00")
+
+var panasonicTagNames = map[uint16]string{
+	PanasonicImageQualityTagID:         "ImageQuality",
+	PanasonicShootingModeTagID:         "ShootingMode",
+	PanasonicInternalSerialNumberTagID: "InternalSerialNumber",
+	PanasonicBurstModeTagID:            "BurstMode",
+	PanasonicProgramIsoTagID:           "ProgramISO",
+	PanasonicLensTypeTagID:             "LensType",
+	PanasonicLensSerialNumberTagID:     "LensSerialNumber",
+	PanasonicAccessoryTypeTagID:        "AccessoryType",
+	PanasonicSceneModeTagID:            "SceneMode",
+}
+
+func init() {
+	for id, name := range panasonicTagNames {
+		tagNames[fmt.Sprintf("%04x/%04x/%04x", exifTagID, makerNotesTagID, id)] = "Panasonic " + name
+	}
+}
+
+func panasonicDetector(data []byte) bool {
+	return bytes.HasPrefix(data, panasonicHeader)
+}
+
+// Panasonic maker notes are IFD after 12 bytes header. Offsets are relative to the main TIFF header, but byte order
+// of the older cameras may differ from the main TIFF structure
+func panasonicReader(file File, entry ifdEntry) (*ifd, error) {
+	if len(entry.ValueBytes) < len(panasonicHeader)+2 {
+		return nil, nil
+	}
+	order := guessIfdByteOrder(entry.ValueBytes[len(panasonicHeader):], file.GetOrder())
+	return readRelativeIfd(file, file.GetTiffHeaderOffset(), int64(entry.Data)+int64(len(panasonicHeader)), order,
+		entry.IfdIndex)
+}
@@ -0,0 +1,28 @@
+package exif
+
+import (
+	"testing"
+)
+
+func TestReadingPanasonicMakerNotes(t *testing.T) {
+	tagMap := readTestTags(t, "../test-data/cameras/Panasonic/PanasonicGX1.jpeg")
+
+	if tag, ok := tagMap["8769/927c/0051"]; !ok || tag.Value.(string) != "LUMIX G VARIO 14-42/F3.5-5.6" || tag.Name() != "Panasonic LensType" {
+		t.Fatalf("Failed to read Panasonic lens type")
+	}
+	if tag, ok := tagMap["8769/927c/0052"]; !ok || tag.Value.(string) != "11SG42705521" {
+		t.Fatalf("Failed to read Panasonic lens serial number")
+	}
+	if tag, ok := tagMap["8769/927c/001f"]; !ok || tag.Value.([]uint16)[0] != 37 {
+		t.Fatalf("Failed to read Panasonic shooting mode")
+	}
+}
+
+// older Panasonic cameras write little endian maker notes into big endian TIFF structure
+func TestReadingPanasonicMakerNotesWithOwnByteOrder(t *testing.T) {
+	tagMap := readTestTags(t, "../test-data/scan/P1020297.JPG")
+
+	if tag, ok := tagMap["8769/927c/001f"]; !ok || tag.Value.([]uint16)[0] != 3 {
+		t.Fatalf("Failed to read Panasonic shooting mode")
+	}
+}
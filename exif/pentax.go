@@ -0,0 +1,116 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// Pentax maker notes tags. Ricoh cameras made after Pentax merger use the same tags
+const (
+	PentaxModelIDTagID            = 0x0005
+	PentaxDateTagID               = 0x0006
+	PentaxTimeTagID               = 0x0007
+	PentaxQualityTagID            = 0x0008
+	PentaxLensRecTagID            = 0x003f
+	PentaxShakeReductionInfoTagID = 0x005c
+	PentaxShutterCountTagID       = 0x005d
+	PentaxSerialNumberTagID       = 0x0229
+)
+
+// Pentax maker notes come in three flavours:
+//   - "AOC\0" followed by byte order mark, offsets are relative to the main TIFF header;
+//   - "PENTAX \0" followed by byte order mark, offsets are relative to the maker notes;
+//   - "RICOH\0" followed by byte order mark of Ricoh GR cameras, offsets are relative to the maker notes
+var (
+	pentaxAocHeader = []byte("AOC\x00")
+	pentaxHeader    = []byte("PENTAX \x00")
+	ricohHeader     = []byte("RICOH\x00")
+)
+
+var pentaxTagNames = map[uint16]string{
+	PentaxModelIDTagID:            "PentaxModelID",
+	PentaxDateTagID:               "Date",
+	PentaxTimeTagID:               "Time",
+	PentaxQualityTagID:            "Quality",
+	PentaxLensRecTagID:            "LensRec",
+	PentaxShakeReductionInfoTagID: "ShakeReductionInfo",
+	PentaxShutterCountTagID:       "ShutterCount",
+	PentaxSerialNumberTagID:       "SerialNumber",
+}
+
+func init() {
+	for id, name := range pentaxTagNames {
+		tagNames[fmt.Sprintf("%04x/%04x/%04x", exifTagID, makerNotesTagID, id)] = "Pentax " + name
+	}
+}
+
+func pentaxDetector(data []byte) bool {
+	return bytes.HasPrefix(data, pentaxAocHeader) || bytes.HasPrefix(data, pentaxHeader) ||
+		(bytes.HasPrefix(data, ricohHeader) && len(data) > len(ricohHeader)+2 &&
+			(data[len(ricohHeader)] == LittleEndian || data[len(ricohHeader)] == BigEndian))
+}
+
+func pentaxReader(file File, entry ifdEntry) (*ifd, error) {
+	base := file.GetTiffHeaderOffset()
+	var header []byte
+	switch {
+	case bytes.HasPrefix(entry.ValueBytes, pentaxAocHeader):
+		header = pentaxAocHeader
+	case bytes.HasPrefix(entry.ValueBytes, pentaxHeader):
+		header = pentaxHeader
+		base += int64(entry.Data)
+	default:
+		header = ricohHeader
+		base += int64(entry.Data)
+	}
+	if len(entry.ValueBytes) < len(header)+4 {
+		return nil, nil
+	}
+	ifdOffset := int64(len(header) + 2)
+	if header[0] == pentaxAocHeader[0] {
+		ifdOffset += int64(entry.Data)
+	}
+	// some of the older cameras have two spaces instead of byte order mark
+	order := entry.ValueBytes[len(header)]
+	if order != LittleEndian && order != BigEndian {
+		order = guessIfdByteOrder(entry.ValueBytes[len(header)+2:], file.GetOrder())
+	}
+	return readRelativeIfd(file, base, ifdOffset, order, entry.IfdIndex)
+}
+
+// pentaxShutterCount decrypts shutter count with the date and time of the shot as the key
+func pentaxShutterCount(count []byte, date []byte, time []byte) (uint32, bool) {
+	if len(count) != 4 || len(date) < 4 || len(time) < 3 {
+		return 0, false
+	}
+	dateKey := binary.BigEndian.Uint32(date)
+	timeKey := uint32(time[0])<<24 | uint32(time[1])<<16 | uint32(time[2])<<8
+	return binary.BigEndian.Uint32(count) ^ dateKey ^ ^timeKey, true
+}
+
+// decodePentaxMakerNote replaces encrypted shutter count with the decrypted value
+func decodePentaxMakerNote(file File, parentIDs []uint16, tags Tags, camera cameraIdentity) Tags {
+	var date, time []byte
+	for _, tag := range tags {
+		switch tag.ID {
+		case PentaxDateTagID:
+			date = tag.RawData
+		case PentaxTimeTagID:
+			time = tag.RawData
+		}
+	}
+	result := make(Tags, 0, len(tags))
+	for _, tag := range tags {
+		if tag.ID == PentaxShutterCountTagID {
+			count, ok := pentaxShutterCount(tag.RawData, date, time)
+			if !ok {
+				continue
+			}
+			tag.DataType = TypeUnsignedLong
+			tag.Value = []uint32{count}
+		}
+		result = append(result, tag)
+	}
+	return result
+}
@@ -0,0 +1,52 @@
+package exif
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestReadingPentaxMakerNotes(t *testing.T) {
+	date := []byte{0x07, 0xDC, 0x08, 0x06}
+	time := []byte{10, 47, 3}
+	encrypted := make([]byte, 4)
+	binary.BigEndian.PutUint32(encrypted, 12345^0x07DC0806^^uint32(0x0A2F0300))
+	// "AOC" maker notes are small enough to keep all the values inside IFD, so that offsets do not matter
+	makerNote := buildMakerNote(binary.LittleEndian, []byte("AOC\x00II"), []tiffEntry{
+		undefinedEntry(PentaxDateTagID, date),
+		undefinedEntry(PentaxTimeTagID, time),
+		byteEntry(PentaxLensRecTagID, 7, 243),
+		undefinedEntry(PentaxShakeReductionInfoTagID, []byte{0, 1, 0, 0}),
+		undefinedEntry(PentaxShutterCountTagID, encrypted),
+	})
+	tiff := buildTiff([]tiffEntry{
+		asciiEntry(0x010f, "PENTAX Corporation"),
+		{ID: exifTagID, Sub: []tiffEntry{
+			undefinedEntry(makerNotesTagID, makerNote),
+		}},
+	})
+	tagMap := readTestTags(t, writeTempFile(t, ".jpg", buildJpeg(tiff)))
+
+	if tag, ok := tagMap["8769/927c/005d"]; !ok || tag.Value.([]uint32)[0] != 12345 || tag.Name() != "Pentax ShutterCount" {
+		t.Fatalf("Failed to decrypt Pentax shutter count: %v", tag.Value)
+	}
+	if tag, ok := tagMap["8769/927c/003f"]; !ok || tag.Value.([]byte)[0] != 7 || tag.Value.([]byte)[1] != 243 {
+		t.Fatalf("Failed to read Pentax lens record")
+	}
+}
+
+func TestReadingRicohMakerNotes(t *testing.T) {
+	makerNote := buildMakerNote(binary.BigEndian, []byte("RICOH\x00MM"), []tiffEntry{
+		asciiEntry(PentaxSerialNumberTagID, "1234567"),
+	})
+	tiff := buildTiffWithOrder(binary.LittleEndian, []tiffEntry{
+		asciiEntry(0x010f, "RICOH IMAGING COMPANY, LTD."),
+		{ID: exifTagID, Sub: []tiffEntry{
+			undefinedEntry(makerNotesTagID, makerNote),
+		}},
+	})
+	tagMap := readTestTags(t, writeTempFile(t, ".jpg", buildJpeg(tiff)))
+
+	if tag, ok := tagMap["8769/927c/0229"]; !ok || tag.Value.(string) != "1234567" {
+		t.Fatalf("Failed to read Ricoh serial number")
+	}
+}
@@ -0,0 +1,67 @@
+package exif
+
+import (
+	"bytes"
+	"fmt"
+)
+
+var pngSignature = []byte{0x89, 'P', 'N', 'G', 0x0D, 0x0A, 0x1A, 0x0A}
+
+func pngDetector(header []byte) bool {
+	return bytes.HasPrefix(header, pngSignature)
+}
+
+// readPngTags reads tags from the eXIf chunk of PNG file. eXIf chunk contains raw TIFF structure
+func readPngTags(file File) (Tags, error) {
+	file.SetOrder(BigEndian) // PNG chunks are always big endian
+	_, err := file.seek(int64(len(pngSignature)))
+	if err != nil {
+		return nil, err
+	}
+	for {
+		length, err := file.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		chunkType, err := file.readBytes(4)
+		if err != nil {
+			return nil, err
+		}
+		switch string(chunkType) {
+		case "eXIf":
+			pos, err := file.currentPosition()
+			if err != nil {
+				return nil, err
+			}
+			return readRawExifBlock(file, pos)
+		case "IDAT", "IEND":
+			// eXIf must precede image data, file does not contain exif
+			return make(Tags, 0), nil
+		}
+		_, err = file.seekRelative(int64(length) + 4) // skip chunk data and CRC
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readRawExifBlock reads TIFF block stored in a container chunk. Some writers put "Exif\0\0" identifier before the
+// TIFF header, it is skipped if present
+func readRawExifBlock(file File, offset int64) (Tags, error) {
+	_, err := file.seek(offset)
+	if err != nil {
+		return nil, err
+	}
+	identifier, err := file.readBytes(6)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(identifier, []byte{'E', 'x', 'i', 'f', 0, 0}) {
+		offset += 6
+	}
+	tags, err := readTiffBlock(file, offset)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Exif block in %s: %v", file.GetPath(), err)
+	}
+	return tags, nil
+}
@@ -0,0 +1,49 @@
+package exif
+
+import (
+	"bytes"
+	"fmt"
+)
+
+const (
+	rafMagic = "FUJIFILMCCD-RAW"
+	// offset of the JPEG preview offset and length in RAF header offset table
+	rafJpegOffsetPosition = 0x54
+)
+
+func rafDetector(header []byte) bool {
+	return bytes.HasPrefix(header, []byte(rafMagic))
+}
+
+// readRafTags reads tags from Fujifilm RAF files. RAF header contains offset table which points to the embedded
+// JPEG preview. Exif data is read from that JPEG
+func readRafTags(file File) (Tags, error) {
+	file.SetOrder(BigEndian) // RAF header is always big endian
+	_, err := file.seek(rafJpegOffsetPosition)
+	if err != nil {
+		return nil, err
+	}
+	jpegOffset, err := file.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	jpegLength, err := file.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	if jpegOffset == 0 || jpegLength == 0 {
+		return nil, fmt.Errorf("No embedded JPEG in RAF file %s", file.GetPath())
+	}
+	_, err = file.seek(int64(jpegOffset))
+	if err != nil {
+		return nil, err
+	}
+	soi, err := file.readUint16()
+	if err != nil {
+		return nil, err
+	}
+	if soi != soiDataMarker {
+		return nil, fmt.Errorf("Invalid embedded JPEG in RAF file %s", file.GetPath())
+	}
+	return readJpegTagsAt(file, int64(jpegOffset))
+}
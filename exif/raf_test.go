@@ -0,0 +1,23 @@
+package exif
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestReadingRaf(t *testing.T) {
+	embedded := buildJpeg(buildTiff([]tiffEntry{
+		asciiEntry(0x010f, "FUJIFILM"),
+		asciiEntry(0x0110, "X-S10"),
+	}))
+	raf := make([]byte, 0x100)
+	copy(raf, []byte("FUJIFILMCCD-RAW 0201FF383501X-S10"))
+	binary.BigEndian.PutUint32(raf[rafJpegOffsetPosition:], uint32(len(raf)))
+	binary.BigEndian.PutUint32(raf[rafJpegOffsetPosition+4:], uint32(len(embedded)))
+	raf = append(raf, embedded...)
+
+	tagMap := readTestTags(t, writeTempFile(t, ".raf", raf))
+	if tag, ok := tagMap[model]; !ok || tag.Value.(string) != "X-S10" {
+		t.Fatalf("Failed to read model from RAF file: %v", tagMap)
+	}
+}
@@ -0,0 +1,42 @@
+package exif
+
+import (
+	"fmt"
+
+	"github.com/uaraven/exif-stat/logger"
+)
+
+const (
+	rw2JpgFromRawTagID = 0x002e
+)
+
+func rw2Detector(header []byte) bool {
+	return header[0] == 'I' && header[1] == 'I' && header[2] == 0x55 && header[3] == 0
+}
+
+// readRw2Tags reads tags from Panasonic RW2 files. RW2 is a TIFF-like file with the magic 0x55 in the header. IFD0
+// contains Panasonic-specific raw tags, i.e. ISO (0x0017) and sensor dimensions (0x0002, 0x0003), and the embedded
+// JPEG image (0x002e) with its own full Exif data. Tags from the embedded JPEG go first, so that tags read from the
+// raw IFDs win when converted to map
+func readRw2Tags(file File) (Tags, error) {
+	ifds, err := readTiffIfds(file)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := ifdsToTags(file, ifds)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range ifds[0].IfdEntries {
+		if entry.TagID != rw2JpgFromRawTagID {
+			continue
+		}
+		jpegTags, err := readJpegTagsAt(file, int64(entry.Data))
+		if err != nil {
+			logger.Verbose(2, fmt.Sprintf("Failed to read embedded JPEG in %s: %v", file.GetPath(), err))
+			break
+		}
+		return append(jpegTags, tags...), nil
+	}
+	return tags, nil
+}
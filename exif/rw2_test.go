@@ -0,0 +1,33 @@
+package exif
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestReadingRw2(t *testing.T) {
+	embedded := buildJpeg(buildTiff([]tiffEntry{
+		asciiEntry(0x010f, "Panasonic"),
+		{ID: exifTagID, Sub: []tiffEntry{
+			shortEntry(0x8827, 200),
+		}},
+	}))
+	rw2 := buildTiffWithOrder(binary.LittleEndian, []tiffEntry{
+		shortEntry(0x0002, 4608),
+		shortEntry(0x0017, 200),
+		asciiEntry(0x010f, "Panasonic"),
+		undefinedEntry(rw2JpgFromRawTagID, embedded),
+	})
+	rw2[2], rw2[3] = 0x55, 0
+
+	tagMap := readTestTags(t, writeTempFile(t, ".rw2", rw2))
+	if tag, ok := tagMap["0017"]; !ok || tag.Value.([]uint16)[0] != 200 {
+		t.Fatalf("Failed to read Panasonic raw ISO: %v", tagMap)
+	}
+	if tag, ok := tagMap["0002"]; !ok || tag.Value.([]uint16)[0] != 4608 {
+		t.Fatalf("Failed to read Panasonic sensor width: %v", tagMap)
+	}
+	if tag, ok := tagMap[iso]; !ok || tag.Value.([]uint16)[0] != 200 {
+		t.Fatalf("Failed to read Exif from embedded JPEG: %v", tagMap)
+	}
+}
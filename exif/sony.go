@@ -0,0 +1,163 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+)
+
+// Sony maker notes tags
+const (
+	SonyTag2010ID = 0x2010
+	SonyTag9050ID = 0x9050
+
+	// offsets of the values in deciphered blocks. Values are exposed as tags with the offset as tag ID, i.e. shutter
+	// count has path "8769/927c/9050/0032"
+	SonyShutterCountOffset = 0x0032
+	SonyLensMountOffset    = 0x0105
+	SonyLensTypeEOffset    = 0x0107 // lens type of E-mount lenses
+	SonyLensTypeAOffset    = 0x0109 // lens type of A-mount lenses, including adapted ones
+	SonyIsoOffset          = 0x1218
+)
+
+// Sony lens mounts
+const (
+	SonyLensMountA = 1
+	SonyLensMountE = 2
+)
+
+var sonyHeaders = [][]byte{
+	[]byte("SONY DSC \x00\x00\x00"),
+	[]byte("SONY CAM \x00\x00\x00"),
+}
+
+const sonyHeaderSize = 12
+
+// layout of the values in the enciphered blocks differs between camera generations. Only the layouts of 2010-2013
+// NEX and SLT cameras are known here
+var (
+	sonyTag9050aModels = regexp.MustCompile(`^(NEX-|SLT-|DSC-RX1$|DSC-RX100$)`)
+	sonyTag2010bModels = regexp.MustCompile(`^(NEX-3N|NEX-5R|NEX-6|NEX-VG30|NEX-VG900|SLT-A99|DSC-RX1|DSC-RX100)$`)
+)
+
+type sonyBlockValue struct {
+	Offset   uint16
+	DataType int
+	Name     string
+}
+
+var sonyTag9050aValues = []sonyBlockValue{
+	{SonyShutterCountOffset, TypeUnsignedLong, "ShutterCount"},
+	{SonyLensMountOffset, TypeUnsignedByte, "LensMount"},
+	{SonyLensTypeEOffset, TypeUnsignedShort, "LensType2"},
+	{SonyLensTypeAOffset, TypeUnsignedShort, "LensType"},
+}
+
+var sonyTag2010bValues = []sonyBlockValue{
+	{SonyIsoOffset, TypeUnsignedShort, "SonyISO"},
+}
+
+// Sony enciphers some of the maker notes blocks by replacing every byte b < 249 with b^3 mod 249
+var sonyDecipherTable = func() [256]byte {
+	var table [256]byte
+	for b := 0; b < 256; b++ {
+		if b < 249 {
+			table[(b*b*b)%249] = byte(b)
+		} else {
+			table[b] = byte(b)
+		}
+	}
+	return table
+}()
+
+func init() {
+	for _, block := range []struct {
+		ID     uint16
+		Values []sonyBlockValue
+	}{{SonyTag9050ID, sonyTag9050aValues}, {SonyTag2010ID, sonyTag2010bValues}} {
+		for _, value := range block.Values {
+			tagNames[fmt.Sprintf("%04x/%04x/%04x/%04x", exifTagID, makerNotesTagID, block.ID, value.Offset)] = "Sony " + value.Name
+		}
+	}
+}
+
+func sonyDetector(data []byte) bool {
+	for _, header := range sonyHeaders {
+		if bytes.HasPrefix(data, header) {
+			return true
+		}
+	}
+	return false
+}
+
+// sonyDecipher returns deciphered copy of the data
+func sonyDecipher(data []byte) []byte {
+	result := make([]byte, len(data))
+	for i, b := range data {
+		result[i] = sonyDecipherTable[b]
+	}
+	return result
+}
+
+// Sony maker notes are IFD after 12 bytes header. Offsets are relative to the main TIFF header
+func sonyReader(file File, entry ifdEntry) (*ifd, error) {
+	return readIfd(file, int64(entry.Data)+sonyHeaderSize, entry.IfdIndex)
+}
+
+// sonyBlockValueTag reads a value from deciphered Sony block. Sony blocks are always little endian
+func sonyBlockValueTag(parentIDs []uint16, data []byte, value sonyBlockValue) (Tag, bool) {
+	offset := int(value.Offset)
+	tag := Tag{ID: value.Offset, IDPath: parentIDs, DataType: value.DataType}
+	switch value.DataType {
+	case TypeUnsignedByte:
+		if offset+1 > len(data) {
+			return tag, false
+		}
+		tag.Value = []byte{data[offset]}
+	case TypeUnsignedShort:
+		if offset+2 > len(data) {
+			return tag, false
+		}
+		tag.Value = []uint16{binary.LittleEndian.Uint16(data[offset:])}
+	case TypeUnsignedLong:
+		if offset+4 > len(data) {
+			return tag, false
+		}
+		// the highest byte of shutter count is not a part of the value
+		tag.Value = []uint32{binary.LittleEndian.Uint32(data[offset:]) & 0x00FFFFFF}
+	}
+	return tag, true
+}
+
+// decodeSonyMakerNote deciphers 0x9050 and 0x2010 blocks and adds tags for the known values of the camera model
+func decodeSonyMakerNote(file File, parentIDs []uint16, tags Tags, camera cameraIdentity) Tags {
+	result := make(Tags, 0, len(tags))
+	for _, tag := range tags {
+		var values []sonyBlockValue
+		switch {
+		case tag.ID == SonyTag9050ID && sonyTag9050aModels.MatchString(camera.Model):
+			values = sonyTag9050aValues
+		case tag.ID == SonyTag2010ID && sonyTag2010bModels.MatchString(camera.Model):
+			values = sonyTag2010bValues
+		case tag.ID == SonyTag9050ID || tag.ID == SonyTag2010ID:
+		default:
+			result = append(result, tag)
+			continue
+		}
+		data, ok := tag.Value.([]byte)
+		if !ok {
+			result = append(result, tag)
+			continue
+		}
+		tag.Value = sonyDecipher(data)
+		tag.RawData = tag.Value.([]byte)
+		result = append(result, tag)
+		for _, value := range values {
+			if valueTag, ok := sonyBlockValueTag(childPath(parentIDs, tag.ID), tag.RawData, value); ok {
+				result = append(result, valueTag)
+			}
+		}
+	}
+	return result
+}
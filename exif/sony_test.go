@@ -0,0 +1,69 @@
+package exif
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// sonyEncipher is the inverse of sonyDecipher
+func sonyEncipher(data []byte) []byte {
+	result := make([]byte, len(data))
+	for i, b := range data {
+		if b < 249 {
+			result[i] = byte((int(b) * int(b) * int(b)) % 249)
+		} else {
+			result[i] = b
+		}
+	}
+	return result
+}
+
+func TestSonyDecipher(t *testing.T) {
+	data := make([]byte, 256)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	deciphered := sonyDecipher(sonyEncipher(data))
+	for i := range data {
+		if deciphered[i] != data[i] {
+			t.Fatalf("Invalid deciphered byte %d: %d", i, deciphered[i])
+		}
+	}
+}
+
+func TestReadingSonyMakerNotes(t *testing.T) {
+	tag9050 := make([]byte, 0x200)
+	binary.LittleEndian.PutUint32(tag9050[SonyShutterCountOffset:], 0xAB012345)
+	tag9050[SonyLensMountOffset] = SonyLensMountE
+	binary.LittleEndian.PutUint16(tag9050[SonyLensTypeEOffset:], 32785)
+	tag2010 := make([]byte, 0x1400)
+	binary.LittleEndian.PutUint16(tag2010[SonyIsoOffset:], 0x0900)
+
+	tiff := buildTiff([]tiffEntry{
+		asciiEntry(0x010f, "SONY"),
+		asciiEntry(0x0110, "NEX-3N"),
+		{ID: exifTagID, Sub: []tiffEntry{
+			{ID: makerNotesTagID, Type: TypeUndefined, Header: sonyHeaders[0], Sub: []tiffEntry{
+				undefinedEntry(SonyTag2010ID, sonyEncipher(tag2010)),
+				undefinedEntry(SonyTag9050ID, sonyEncipher(tag9050)),
+			}},
+		}},
+	})
+	tagMap := readTestTags(t, writeTempFile(t, ".jpg", buildJpeg(tiff)))
+
+	if tag, ok := tagMap["8769/927c/9050/0032"]; !ok || tag.Value.([]uint32)[0] != 0x012345 {
+		t.Fatalf("Invalid Sony shutter count: %v", tag.Value)
+	}
+	if tag, ok := tagMap["8769/927c/9050/0105"]; !ok || tag.Value.([]byte)[0] != SonyLensMountE {
+		t.Fatalf("Invalid Sony lens mount: %v", tag.Value)
+	}
+	if tag, ok := tagMap["8769/927c/9050/0107"]; !ok || tag.Value.([]uint16)[0] != 32785 {
+		t.Fatalf("Invalid Sony E-mount lens type: %v", tag.Value)
+	}
+	if tag, ok := tagMap["8769/927c/2010/1218"]; !ok || tag.Value.([]uint16)[0] != 0x0900 {
+		t.Fatalf("Invalid Sony ISO: %v", tag.Value)
+	}
+	if tag := tagMap["8769/927c/9050"]; tag.Value.([]byte)[SonyLensMountOffset] != SonyLensMountE {
+		t.Fatalf("Sony block must be deciphered")
+	}
+}
@@ -0,0 +1,52 @@
+package exif
+
+func tiffDetector(header []byte) bool {
+	if header[0] == LittleEndian && header[1] == LittleEndian {
+		return tiffMagics[uint16(header[3])<<8|uint16(header[2])]
+	}
+	if header[0] == BigEndian && header[1] == BigEndian {
+		return tiffMagics[uint16(header[2])<<8|uint16(header[3])]
+	}
+	return false
+}
+
+// readTiffTags reads tags from TIFF-based files, including most of the RAW formats, i.e. NEF, ARW, DNG, PEF or CR2.
+// Such files start with the TIFF header at offset 0
+func readTiffTags(file File) (Tags, error) {
+	ifds, err := readTiffIfds(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return ifdsToTags(file, ifds)
+}
+
+func readTiffIfds(file File) ([]ifd, error) {
+	return readTiffIfdsAt(file, 0)
+}
+
+// readTiffIfdsAt reads all IFDs of the TIFF structure which starts at the given offset in the file
+func readTiffIfdsAt(file File, offset int64) ([]ifd, error) {
+	_, err := file.seek(offset)
+	if err != nil {
+		return nil, err
+	}
+	file.SetTiffHeaderOffset(offset)
+	err = readTiffHeader(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return readIfds(file)
+}
+
+// readTiffBlock reads tags from the TIFF structure which starts at the given offset in the file. Used for the
+// container formats which store Exif data as a raw TIFF block
+func readTiffBlock(file File, offset int64) (Tags, error) {
+	ifds, err := readTiffIfdsAt(file, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return ifdsToTags(file, ifds)
+}
@@ -0,0 +1,46 @@
+package exif
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestReadingTiffRaw(t *testing.T) {
+	tiff := buildTiffWithOrder(binary.LittleEndian, []tiffEntry{
+		asciiEntry(0x010f, "NIKON CORPORATION"),
+		{ID: subIfdsTagID, Sub: []tiffEntry{
+			longEntry(0x0100, 6016),
+		}},
+		{ID: exifTagID, Sub: []tiffEntry{
+			shortEntry(0x8827, 200),
+		}},
+	})
+	tagMap := readTestTags(t, writeTempFile(t, ".nef", tiff))
+	if tag, ok := tagMap[cameraMake]; !ok || tag.Value.(string) != "NIKON CORPORATION" {
+		t.Fatalf("Failed to read make from TIFF file: %v", tagMap)
+	}
+	if tag, ok := tagMap["014a/0000/0100"]; !ok || tag.Value.([]uint32)[0] != 6016 {
+		t.Fatalf("Failed to read SubIFD tags: %v", tagMap)
+	}
+	if tag, ok := tagMap[iso]; !ok || tag.Value.([]uint16)[0] != 200 {
+		t.Fatalf("Failed to read Exif IFD tags: %v", tagMap)
+	}
+}
+
+func TestReadingOrfHeader(t *testing.T) {
+	tiff := buildTiff([]tiffEntry{
+		asciiEntry(0x010f, "OLYMPUS IMAGING CORP."),
+	})
+	tiff[2], tiff[3] = 'O', 'R'
+	tagMap := readTestTags(t, writeTempFile(t, ".orf", tiff))
+	if tag, ok := tagMap[cameraMake]; !ok || tag.Value.(string) != "OLYMPUS IMAGING CORP." {
+		t.Fatalf("Failed to read make from ORF file: %v", tagMap)
+	}
+}
+
+func TestReadingUnsupportedFile(t *testing.T) {
+	_, err := OpenExifFileIo(writeTempFile(t, ".txt", []byte("definitely not an image")))
+	if err == nil {
+		t.Fatalf("Should have failed to open unsupported file")
+	}
+}
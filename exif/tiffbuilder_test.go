@@ -0,0 +1,221 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// tiffEntry describes an IFD entry for the synthetic TIFF structures used in tests.
+// If Sub is not nil, then the entry is a pointer to a sub-IFD and Data is ignored. Sub-IFD entry of TypeUndefined
+// embeds the IFD as a value, the way maker notes are stored, Header is written before such IFD
+type tiffEntry struct {
+	ID     uint16
+	Type   uint16
+	Count  uint32
+	Data   []byte
+	Sub    []tiffEntry
+	Header []byte
+}
+
+type tiffBuilder struct {
+	buf   bytes.Buffer
+	order binary.ByteOrder
+}
+
+func (b *tiffBuilder) patch32(pos int, value uint32) {
+	b.order.PutUint32(b.buf.Bytes()[pos:pos+4], value)
+}
+
+func (b *tiffBuilder) write(value interface{}) {
+	binary.Write(&b.buf, b.order, value)
+}
+
+// entryData returns entry value in the byte order of the builder. Values in entries are always stored in big endian
+func (b *tiffBuilder) entryData(entry tiffEntry) []byte {
+	size := 1
+	switch entry.Type {
+	case TypeUnsignedShort, TypeSignedShort:
+		size = 2
+	case TypeUnsignedLong, TypeSignedLong, TypeUnsignedRational, TypeSignedRational:
+		size = 4
+	}
+	if size == 1 || b.order == binary.BigEndian {
+		return entry.Data
+	}
+	data := make([]byte, len(entry.Data))
+	for i := 0; i+size <= len(data); i += size {
+		for j := 0; j < size; j++ {
+			data[i+j] = entry.Data[i+size-1-j]
+		}
+	}
+	return data
+}
+
+// writeIfd appends IFD to the buffer and returns the position of "next IFD" offset field
+func (b *tiffBuilder) writeIfd(entries []tiffEntry) (int, int) {
+	start := b.buf.Len()
+	b.write(uint16(len(entries)))
+	valuePositions := make([]int, len(entries))
+	for i, entry := range entries {
+		b.write(entry.ID)
+		if entry.Sub != nil && entry.Type == TypeUndefined {
+			b.write(uint16(TypeUndefined))
+			b.write(uint32(0)) // patched when sub-IFD is written
+		} else if entry.Sub != nil {
+			b.write(uint16(TypeUnsignedLong))
+			b.write(uint32(1))
+		} else {
+			b.write(entry.Type)
+			b.write(entry.Count)
+		}
+		valuePositions[i] = b.buf.Len()
+		value := make([]byte, 4)
+		if entry.Sub == nil && len(entry.Data) <= 4 {
+			copy(value, b.entryData(entry))
+		}
+		b.buf.Write(value)
+	}
+	nextPos := b.buf.Len()
+	b.write(uint32(0))
+	for i, entry := range entries {
+		if entry.Sub != nil {
+			valueStart := b.buf.Len()
+			b.buf.Write(entry.Header)
+			subStart, _ := b.writeIfd(entry.Sub)
+			if entry.Type == TypeUndefined {
+				b.patch32(valuePositions[i], uint32(valueStart))
+				b.patch32(valuePositions[i]-4, uint32(b.buf.Len()-valueStart))
+			} else {
+				b.patch32(valuePositions[i], uint32(subStart))
+			}
+		} else if len(entry.Data) > 4 {
+			b.patch32(valuePositions[i], uint32(b.buf.Len()))
+			b.buf.Write(b.entryData(entry))
+		}
+	}
+	return start, nextPos
+}
+
+// buildTiff creates a big-endian TIFF structure with the chain of given IFDs
+func buildTiff(ifds ...[]tiffEntry) []byte {
+	return buildTiffWithOrder(binary.BigEndian, ifds...)
+}
+
+func buildTiffWithOrder(order binary.ByteOrder, ifds ...[]tiffEntry) []byte {
+	b := &tiffBuilder{order: order}
+	if order == binary.BigEndian {
+		b.buf.Write([]byte{'M', 'M', 0, 0x2A})
+	} else {
+		b.buf.Write([]byte{'I', 'I', 0x2A, 0})
+	}
+	b.write(uint32(8))
+	nextPos := 4
+	for _, entries := range ifds {
+		start, next := b.writeIfd(entries)
+		b.patch32(nextPos, uint32(start))
+		nextPos = next
+	}
+	return b.buf.Bytes()
+}
+
+// buildMakerNote creates maker notes with the given header followed by IFD. Offsets in the IFD are relative to the
+// start of the header
+func buildMakerNote(order binary.ByteOrder, header []byte, entries []tiffEntry) []byte {
+	b := &tiffBuilder{order: order}
+	b.buf.Write(header)
+	b.writeIfd(entries)
+	return b.buf.Bytes()
+}
+
+// buildJpeg wraps TIFF data into a minimal JPEG file with APP1 Exif segment
+func buildJpeg(tiff []byte) []byte {
+	return buildJpegWithSegments(jpegSegment(exifDataMarker, append([]byte{'E', 'x', 'i', 'f', 0, 0}, tiff...)))
+}
+
+// jpegSegment creates JPEG segment with the given marker and payload
+func jpegSegment(marker uint16, data []byte) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, marker)
+	binary.Write(&buf, binary.BigEndian, uint16(len(data)+2))
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+// buildJpegWithSegments creates a minimal JPEG file with the given segments before the start of scan
+func buildJpegWithSegments(segments ...[]byte) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0xFF, 0xD8})
+	for _, segment := range segments {
+		buf.Write(segment)
+	}
+	buf.Write([]byte{0xFF, 0xDA, 0x00, 0x02, 0xFF, 0xD9})
+	return buf.Bytes()
+}
+
+func asciiEntry(id uint16, value string) tiffEntry {
+	data := append([]byte(value), 0)
+	return tiffEntry{ID: id, Type: TypeASCIItring, Count: uint32(len(data)), Data: data}
+}
+
+func shortEntry(id uint16, values ...uint16) tiffEntry {
+	data := make([]byte, len(values)*2)
+	for i, v := range values {
+		binary.BigEndian.PutUint16(data[i*2:], v)
+	}
+	return tiffEntry{ID: id, Type: TypeUnsignedShort, Count: uint32(len(values)), Data: data}
+}
+
+func longEntry(id uint16, values ...uint32) tiffEntry {
+	data := make([]byte, len(values)*4)
+	for i, v := range values {
+		binary.BigEndian.PutUint32(data[i*4:], v)
+	}
+	return tiffEntry{ID: id, Type: TypeUnsignedLong, Count: uint32(len(values)), Data: data}
+}
+
+func rationalEntry(id uint16, values ...uint32) tiffEntry {
+	data := make([]byte, len(values)*4)
+	for i, v := range values {
+		binary.BigEndian.PutUint32(data[i*4:], v)
+	}
+	return tiffEntry{ID: id, Type: TypeUnsignedRational, Count: uint32(len(values) / 2), Data: data}
+}
+
+func byteEntry(id uint16, values ...byte) tiffEntry {
+	return tiffEntry{ID: id, Type: TypeUnsignedByte, Count: uint32(len(values)), Data: values}
+}
+
+func undefinedEntry(id uint16, data []byte) tiffEntry {
+	return tiffEntry{ID: id, Type: TypeUndefined, Count: uint32(len(data)), Data: data}
+}
+
+// writeTempFile writes data into a temporary file with given extension and returns its path
+func writeTempFile(t *testing.T, ext string, data []byte) string {
+	f, err := ioutil.TempFile("", "exif-stat-*"+ext)
+	if err != nil {
+		t.Fatalf("Failed to create temporary file: %v", err)
+	}
+	defer f.Close()
+	_, err = f.Write(data)
+	if err != nil {
+		t.Fatalf("Failed to write temporary file: %v", err)
+	}
+	t.Cleanup(func() { os.Remove(f.Name()) })
+	return f.Name()
+}
+
+func readTestTags(t *testing.T, path string) map[string]Tag {
+	file, err := OpenExifFileIo(path)
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+	tags, err := ReadExifTags(file)
+	if err != nil {
+		t.Fatalf("Failed to read tags: %v", err)
+	}
+	return TagsAsMap(tags)
+}
@@ -0,0 +1,372 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	dateTimeOriginalTagID = 0x9003
+
+	exifDateFormat = "2006:01:02 15:04:05"
+)
+
+// box types that can start QuickTime or MP4 file. Older QuickTime files do not have "ftyp" box
+var videoBoxTypes = map[string]bool{
+	"ftyp": true,
+	"moov": true,
+	"mdat": true,
+	"wide": true,
+	"free": true,
+	"skip": true,
+	"pnot": true,
+}
+
+// metadata keys of QuickTime user data and "mdta" metadata that are mapped to tags
+var videoMetadataKeys = map[string]string{
+	"\xa9mak":                                "Make",
+	"\xa9mod":                                "Model",
+	"\xa9day":                                "CreateTime",
+	"com.apple.quicktime.make":               "Make",
+	"com.apple.quicktime.model":              "Model",
+	"com.apple.quicktime.creationdate":       "CreateTime",
+	"com.apple.quicktime.content.identifier": "ContentIdentifier",
+}
+
+var videoDateFormats = []string{
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// QuickTime timestamps are seconds since 1904-01-01
+var quickTimeEpoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func videoDetector(header []byte) bool {
+	return videoBoxTypes[string(header[4:8])]
+}
+
+// readVideoTags reads tags from QuickTime and MP4 video files. Creation time is read from "mvhd" box, make and model
+// from "udta" user data or "meta" keys. These values are converted to Make, Model and DateTimeOriginal tags.
+// Exif data that cameras embed into video files (Panasonic PANA box, Canon CNTH and CMT boxes) is read as well and
+// takes precedence over the values from the video metadata
+func readVideoTags(file File) (Tags, error) {
+	topBoxes, err := readTopLevelBoxes(file)
+	if err != nil {
+		return nil, err
+	}
+	moov := findBox(topBoxes, "moov")
+	if moov == nil {
+		return nil, fmt.Errorf("No 'moov' box in %s", file.GetPath())
+	}
+	moovBoxes, err := readChildBoxes(file, moov)
+	if err != nil {
+		return nil, err
+	}
+	metadata := make(map[string]string)
+	if mvhd := findBox(moovBoxes, "mvhd"); mvhd != nil {
+		err = readMovieHeader(file, mvhd, metadata)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if meta := findBox(moovBoxes, "meta"); meta != nil {
+		err = readVideoMeta(file, meta, metadata)
+		if err != nil {
+			return nil, err
+		}
+	}
+	embeddedTags := make(Tags, 0)
+	if udta := findBox(moovBoxes, "udta"); udta != nil {
+		udtaBoxes, err := readChildBoxes(file, udta)
+		if err != nil {
+			return nil, err
+		}
+		err = readUserData(file, udtaBoxes, metadata)
+		if err != nil {
+			return nil, err
+		}
+		tags, err := readEmbeddedVideoExif(file, udtaBoxes)
+		if err != nil {
+			return nil, err
+		}
+		embeddedTags = append(embeddedTags, tags...)
+	}
+	if canon := findUUIDBox(moovBoxes, canonUUID); canon != nil {
+		blockFiles, err := readCanonBlocks(file, canon)
+		if err != nil {
+			return nil, err
+		}
+		tags, err := canonBlocksToTags(blockFiles)
+		if err != nil {
+			return nil, err
+		}
+		embeddedTags = append(embeddedTags, tags...)
+		canonBoxes, err := readChildBoxes(file, canon)
+		if err != nil {
+			return nil, err
+		}
+		tags, err = readEmbeddedVideoExif(file, canonBoxes)
+		if err != nil {
+			return nil, err
+		}
+		embeddedTags = append(embeddedTags, tags...)
+	}
+	return append(videoMetadataToTags(metadata), embeddedTags...), nil
+}
+
+func readMovieHeader(file File, mvhd *bmffBox, metadata map[string]string) error {
+	version, err := readFullBoxVersion(file, mvhd)
+	if err != nil {
+		return err
+	}
+	var creationTime uint64
+	if version == 1 {
+		err = file.Read(&creationTime)
+	} else {
+		var creationTime32 uint32
+		creationTime32, err = file.readUint32()
+		creationTime = uint64(creationTime32)
+	}
+	if err != nil {
+		return err
+	}
+	if creationTime != 0 {
+		metadata["CreateTime"] = quickTimeEpoch.Add(time.Duration(creationTime) * time.Second).Format(exifDateFormat)
+	}
+	return nil
+}
+
+// readUserData reads QuickTime text atoms, i.e. "©mak", and iTunes-style metadata from the "udta" box
+func readUserData(file File, udtaBoxes []bmffBox, metadata map[string]string) error {
+	for _, box := range udtaBoxes {
+		if box.Type == "meta" {
+			err := readVideoMeta(file, &box, metadata)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		name, ok := videoMetadataKeys[box.Type]
+		if !ok {
+			continue
+		}
+		data, err := readBoxData(file, &box)
+		if err != nil {
+			return err
+		}
+		if len(data) >= 8 && string(data[4:8]) == "data" {
+			metadata[name] = dataBoxValue(data)
+		} else if len(data) >= 4 { // QuickTime text: 16-bit size, 16-bit language code, text
+			size := int(binary.BigEndian.Uint16(data))
+			if size > len(data)-4 {
+				size = len(data) - 4
+			}
+			metadata[name] = strings.TrimRight(string(data[4:4+size]), "\x00")
+		}
+	}
+	return nil
+}
+
+// readVideoMeta reads "meta" box. In MP4 "meta" is a full box, in QuickTime it is a regular box. Item keys are
+// either stored in a separate "keys" box, or are the types of item boxes in "ilst" box
+func readVideoMeta(file File, meta *bmffBox, metadata map[string]string) error {
+	_, err := file.seek(meta.Start)
+	if err != nil {
+		return err
+	}
+	start := meta.Start
+	peek, err := file.readBytes(8)
+	if err != nil {
+		return err
+	}
+	if string(peek[4:8]) != "hdlr" {
+		start += 4 // version and flags of the full box
+	}
+	metaBoxes, err := readBoxes(file, start, meta.End)
+	if err != nil {
+		return err
+	}
+	var keys []string
+	if keysBox := findBox(metaBoxes, "keys"); keysBox != nil {
+		keys, err = readMetadataKeys(file, keysBox)
+		if err != nil {
+			return err
+		}
+	}
+	ilst := findBox(metaBoxes, "ilst")
+	if ilst == nil {
+		return nil
+	}
+	items, err := readChildBoxes(file, ilst)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		key := item.Type
+		if keys != nil {
+			index := int(binary.BigEndian.Uint32([]byte(item.Type)))
+			if index < 1 || index > len(keys) {
+				continue
+			}
+			key = keys[index-1]
+		}
+		name, ok := videoMetadataKeys[key]
+		if !ok {
+			continue
+		}
+		data, err := readBoxData(file, &item)
+		if err != nil {
+			return err
+		}
+		metadata[name] = dataBoxValue(data)
+	}
+	return nil
+}
+
+func readMetadataKeys(file File, keysBox *bmffBox) ([]string, error) {
+	_, err := readFullBoxVersion(file, keysBox)
+	if err != nil {
+		return nil, err
+	}
+	count, err := file.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	// every key has at least 4-byte size and 4-byte namespace, version, flags and count take 8 bytes of the box
+	if int64(count) > (keysBox.End-keysBox.Start-8)/8 {
+		return nil, fmt.Errorf("Invalid metadata key count %d in %s", count, file.GetPath())
+	}
+	keys := make([]string, 0, count)
+	for index := uint32(0); index < count; index++ {
+		pos, err := file.currentPosition()
+		if err != nil {
+			return nil, err
+		}
+		size, err := file.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		if size < 8 || pos+int64(size) > keysBox.End {
+			return nil, fmt.Errorf("Invalid metadata key size %d in %s", size, file.GetPath())
+		}
+		_, err = file.readBytes(4) // key namespace
+		if err != nil {
+			return nil, err
+		}
+		key := make([]byte, size-8)
+		err = file.Read(key)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, string(key))
+	}
+	return keys, nil
+}
+
+// dataBoxValue returns value of the "data" box which is the first box in the metadata item payload. "data" box
+// contains 4-byte type, 4-byte locale and the value
+func dataBoxValue(item []byte) string {
+	if len(item) < 16 {
+		return ""
+	}
+	size := int(binary.BigEndian.Uint32(item))
+	if size > len(item) || size < 16 {
+		size = len(item)
+	}
+	return strings.TrimRight(string(item[16:size]), "\x00")
+}
+
+// readEmbeddedVideoExif reads Exif data that cameras put into video files. Panasonic stores a block with TIFF
+// structure in "PANA" box, Canon stores JPEG thumbnail with Exif in "CNDA" box inside "CNTH" box
+func readEmbeddedVideoExif(file File, boxes []bmffBox) (Tags, error) {
+	tags := make(Tags, 0)
+	if pana := findBox(boxes, "PANA"); pana != nil {
+		data, err := readBoxData(file, pana)
+		if err != nil {
+			return nil, err
+		}
+		if offset := findTiffHeader(data); offset >= 0 {
+			panaTags, err := readTiffBlock(file, pana.Start+int64(offset))
+			if err != nil {
+				return nil, err
+			}
+			tags = append(tags, panaTags...)
+		}
+	}
+	if cnth := findBox(boxes, "CNTH"); cnth != nil {
+		cnthBoxes, err := readChildBoxes(file, cnth)
+		if err != nil {
+			return nil, err
+		}
+		if cnda := findBox(cnthBoxes, "CNDA"); cnda != nil {
+			cndaTags, err := readJpegTagsAt(file, cnda.Start)
+			if err != nil {
+				return nil, err
+			}
+			tags = append(tags, cndaTags...)
+		}
+	}
+	return tags, nil
+}
+
+// findTiffHeader returns offset of the first TIFF header in data or -1 if there is none
+func findTiffHeader(data []byte) int {
+	result := -1
+	for _, header := range [][]byte{{'I', 'I', 0x2A, 0}, {'M', 'M', 0, 0x2A}} {
+		offset := bytes.Index(data, header)
+		if offset >= 0 && (result < 0 || offset < result) {
+			result = offset
+		}
+	}
+	return result
+}
+
+func parseVideoDate(value string) (string, bool) {
+	for _, layout := range videoDateFormats {
+		tm, err := time.Parse(layout, value)
+		if err == nil {
+			return tm.Format(exifDateFormat), true
+		}
+	}
+	return "", false
+}
+
+func asciiTag(path []uint16, id uint16, value string) Tag {
+	return Tag{
+		ID:       id,
+		IDPath:   path,
+		DataType: TypeASCIItring,
+		Value:    value,
+		RawData:  []byte(value),
+	}
+}
+
+// videoMetadataToTags converts video metadata to standard Exif tags
+func videoMetadataToTags(metadata map[string]string) Tags {
+	tags := make(Tags, 0)
+	if value, ok := metadata["Make"]; ok && len(value) > 0 {
+		tags = append(tags, asciiTag(nil, makeTagID, value))
+	}
+	if value, ok := metadata["Model"]; ok && len(value) > 0 {
+		tags = append(tags, asciiTag(nil, modelTagID, value))
+	}
+	if value, ok := metadata["CreateTime"]; ok {
+		if strings.Contains(value, "-") { // ISO 8601 date from metadata, needs conversion to Exif format
+			value, ok = parseVideoDate(value)
+		}
+		if ok {
+			tags = append(tags, asciiTag([]uint16{exifTagID}, dateTimeOriginalTagID, value))
+		}
+	}
+	// content identifier has the same path as in Apple maker notes of the paired image
+	if value, ok := metadata["ContentIdentifier"]; ok && len(value) > 0 {
+		tags = append(tags, asciiTag([]uint16{exifTagID, makerNotesTagID}, AppleContentIdentifierTagID, value))
+	}
+	return tags
+}
@@ -0,0 +1,97 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func buildMvhd(created time.Time) []byte {
+	payload := make([]byte, 100)
+	binary.BigEndian.PutUint32(payload[4:], uint32(created.Sub(quickTimeEpoch)/time.Second))
+	return buildBox("mvhd", payload)
+}
+
+func buildQuickTimeText(boxType string, text string) []byte {
+	header := make([]byte, 4)
+	binary.BigEndian.PutUint16(header, uint16(len(text)))
+	return buildBox(boxType, header, []byte(text))
+}
+
+func buildDataBox(value string) []byte {
+	return buildBox("data", []byte{0, 0, 0, 1, 0, 0, 0, 0}, []byte(value))
+}
+
+func buildKeys(keys ...string) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0, 0, 0, 0})
+	binary.Write(&buf, binary.BigEndian, uint32(len(keys)))
+	for _, key := range keys {
+		binary.Write(&buf, binary.BigEndian, uint32(len(key)+8))
+		buf.WriteString("mdta")
+		buf.WriteString(key)
+	}
+	return buildBox("keys", buf.Bytes())
+}
+
+func TestReadingVideo(t *testing.T) {
+	created := time.Date(2021, 1, 9, 10, 25, 9, 0, time.UTC)
+	meta := buildBox("meta",
+		buildBox("hdlr", make([]byte, 24)),
+		buildKeys("com.apple.quicktime.model"),
+		buildBox("ilst", buildBox(string([]byte{0, 0, 0, 1}), buildDataBox("DC-GX9"))),
+	)
+	pana := append([]byte("PANASONIC"), buildTiff([]tiffEntry{
+		{ID: exifTagID, Sub: []tiffEntry{shortEntry(0x8827, 400)}},
+	})...)
+	mp4 := bytes.Join([][]byte{
+		buildFtyp("mp42"),
+		buildBox("moov", buildMvhd(created), meta, buildBox("udta", buildQuickTimeText("\xa9mak", "Panasonic"), buildBox("PANA", pana))),
+		buildBox("mdat", make([]byte, 16)),
+	}, nil)
+
+	path := writeTempFile(t, ".mp4", mp4)
+	tagMap := readTestTags(t, path)
+	if tag, ok := tagMap[cameraMake]; !ok || tag.Value.(string) != "Panasonic" {
+		t.Fatalf("Failed to read make from user data: %v", tagMap)
+	}
+	if tag, ok := tagMap[model]; !ok || tag.Value.(string) != "DC-GX9" {
+		t.Fatalf("Failed to read model from metadata keys: %v", tagMap)
+	}
+	if tag, ok := tagMap["8769/9003"]; !ok || tag.Value.(string) != "2021:01:09 10:25:09" {
+		t.Fatalf("Failed to read creation time from movie header: %v", tagMap)
+	}
+	if tag, ok := tagMap[iso]; !ok || tag.Value.([]uint16)[0] != 400 {
+		t.Fatalf("Failed to read Exif from PANA box: %v", tagMap)
+	}
+
+	file, err := OpenExifFileIo(path)
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+	metadata, err := ReadMetadata(file)
+	if err != nil || metadata.Format != FormatVideo {
+		t.Fatalf("File must be detected as video: %v", err)
+	}
+}
+
+func TestReadingInvalidMetadataKeyCount(t *testing.T) {
+	keys := buildKeys("com.apple.quicktime.model")
+	binary.BigEndian.PutUint32(keys[12:], 0x7fffffff) // count after box header and version
+	file, err := OpenExifFileIo(writeTempFile(t, ".mp4", append(buildFtyp("mp42"), keys...)))
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+
+	boxes, err := readTopLevelBoxes(file)
+	keysBox := findBox(boxes, "keys")
+	if err != nil || keysBox == nil {
+		t.Fatalf("Failed to read keys box: %v", err)
+	}
+	if _, err := readMetadataKeys(file, keysBox); err == nil {
+		t.Fatalf("Key count which does not fit into the box must be reported")
+	}
+}
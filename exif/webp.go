@@ -0,0 +1,41 @@
+package exif
+
+func webpDetector(header []byte) bool {
+	return string(header[0:4]) == "RIFF" && string(header[8:12]) == "WEBP"
+}
+
+// readWebpTags reads tags from the EXIF chunk of WebP file. WebP is a RIFF container, EXIF chunk contains raw TIFF
+// structure
+func readWebpTags(file File) (Tags, error) {
+	file.SetOrder(LittleEndian) // RIFF chunk sizes are little endian
+	_, err := file.seek(4)
+	if err != nil {
+		return nil, err
+	}
+	riffSize, err := file.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	riffEnd := 8 + int64(riffSize)
+	pos := int64(12) // skip RIFF header and WEBP form type
+	for pos+8 <= riffEnd {
+		_, err = file.seek(pos)
+		if err != nil {
+			return nil, err
+		}
+		chunkType, err := file.readBytes(4)
+		if err != nil {
+			return nil, err
+		}
+		size, err := file.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		if string(chunkType) == "EXIF" {
+			return readRawExifBlock(file, pos+8)
+		}
+		pos += 8 + int64(size+size&1) // chunks are padded to even size
+	}
+	// file does not contain exif
+	return make(Tags, 0), nil
+}
@@ -0,0 +1,144 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/uaraven/exif-stat/logger"
+)
+
+const rdfNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+
+// well-known XMP namespaces and their conventional prefixes. Properties are always named with these prefixes,
+// regardless of the prefixes used in the XMP packet
+var xmpNamespaces = map[string]string{
+	"http://ns.adobe.com/xap/1.0/":        "xmp",
+	"http://ns.adobe.com/tiff/1.0/":       "tiff",
+	"http://ns.adobe.com/exif/1.0/":       "exif",
+	"http://ns.adobe.com/exif/1.0/aux/":   "aux",
+	"http://cipa.jp/exif/1.0/":            "exifEX",
+	"http://purl.org/dc/elements/1.1/":    "dc",
+	"http://ns.adobe.com/photoshop/1.0/":  "photoshop",
+	"http://ns.adobe.com/lightroom/1.0/":  "lr",
+	"http://ns.adobe.com/xap/1.0/rights/": "xmpRights",
+}
+
+// XmpProperties maps XMP property names, i.e. "exif:DateTimeOriginal", to their values. Array properties, like
+// "dc:subject", have multiple values
+type XmpProperties map[string][]string
+
+// Get returns the first value of the property
+func (xmp XmpProperties) Get(name string) (string, bool) {
+	values, ok := xmp[name]
+	if !ok || len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// Merge adds all the properties from other XMP properties which are not present in these properties
+func (xmp XmpProperties) Merge(other XmpProperties) {
+	for name, values := range other {
+		if _, ok := xmp[name]; !ok {
+			xmp[name] = values
+		}
+	}
+}
+
+func xmpPropertyName(name xml.Name) string {
+	prefix, ok := xmpNamespaces[name.Space]
+	if !ok {
+		prefix = name.Space
+	}
+	return prefix + ":" + name.Local
+}
+
+func isRdf(name xml.Name, local string) bool {
+	return name.Space == rdfNamespace && name.Local == local
+}
+
+// ParseXmp parses XMP packet and returns the simple and array properties of all rdf:Description elements.
+// Structures are flattened, their fields are returned as top-level properties
+func ParseXmp(data []byte) (XmpProperties, error) {
+	properties := make(XmpProperties)
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	stack := make([]xml.Name, 0)
+	var text strings.Builder
+	var values []string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			if isRdf(element.Name, "Description") {
+				for _, attr := range element.Attr {
+					if attr.Name.Space == "xmlns" || attr.Name.Space == rdfNamespace || attr.Name.Space == "" {
+						continue
+					}
+					name := xmpPropertyName(attr.Name)
+					properties[name] = append(properties[name], attr.Value)
+				}
+			}
+			stack = append(stack, element.Name)
+			text.Reset()
+			if isXmpProperty(stack) {
+				values = nil
+			}
+		case xml.CharData:
+			text.Write(element)
+		case xml.EndElement:
+			if isRdf(element.Name, "li") {
+				values = append(values, strings.TrimSpace(text.String()))
+			} else if isXmpProperty(stack) {
+				if values == nil {
+					if value := strings.TrimSpace(text.String()); len(value) > 0 {
+						values = []string{value}
+					}
+				}
+				if values != nil {
+					name := xmpPropertyName(element.Name)
+					properties[name] = append(properties[name], values...)
+				}
+				values = nil
+			}
+			text.Reset()
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return properties, nil
+}
+
+// element on the top of the stack is a property if it is a direct child of rdf:Description
+func isXmpProperty(stack []xml.Name) bool {
+	return len(stack) > 1 && isRdf(stack[len(stack)-2], "Description") && !isRdf(stack[len(stack)-1], "Description")
+}
+
+// readXmpSegment reads XMP packet from APP1 segment. XMP is a secondary source of metadata, so invalid XMP is
+// reported, but does not fail reading of the file
+func readXmpSegment(file File, marker *marker, identifier string, metadata *Metadata) error {
+	data, err := readSegmentData(file, marker, identifier)
+	if err != nil {
+		return err
+	}
+	xmp, err := ParseXmp(data)
+	if err != nil {
+		logger.Verbose(2, fmt.Sprintf("Invalid XMP in %s: %v", file.GetPath(), err))
+		return nil
+	}
+	if metadata.Xmp == nil {
+		metadata.Xmp = xmp
+	} else {
+		metadata.Xmp.Merge(xmp)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package exif
+
+import (
+	"testing"
+)
+
+const testXmpPacket = `<?xpacket begin="" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+ <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Description rdf:about=""
+    xmlns:xap="http://ns.adobe.com/xap/1.0/"
+    xmlns:aux="http://ns.adobe.com/exif/1.0/aux/"
+    xmlns:exif="http://ns.adobe.com/exif/1.0/"
+    xmlns:dc="http://purl.org/dc/elements/1.1/"
+    xap:Rating="4"
+    aux:Lens="EF24-105mm f/4L IS USM">
+   <exif:DateTimeOriginal>2021-05-01T12:34:56.00+02:00</exif:DateTimeOriginal>
+   <exif:ISOSpeedRatings>
+    <rdf:Seq>
+     <rdf:li>400</rdf:li>
+    </rdf:Seq>
+   </exif:ISOSpeedRatings>
+   <dc:subject>
+    <rdf:Bag>
+     <rdf:li>travel</rdf:li>
+     <rdf:li>mountains</rdf:li>
+    </rdf:Bag>
+   </dc:subject>
+  </rdf:Description>
+ </rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>`
+
+func TestParsingXmp(t *testing.T) {
+	xmp, err := ParseXmp([]byte(testXmpPacket))
+	if err != nil {
+		t.Fatalf("Failed to parse XMP: %v", err)
+	}
+	// prefixes are normalized, xap is the same namespace as xmp
+	if value, ok := xmp.Get("xmp:Rating"); !ok || value != "4" {
+		t.Fatalf("Invalid rating: %v", xmp["xmp:Rating"])
+	}
+	if value, ok := xmp.Get("aux:Lens"); !ok || value != "EF24-105mm f/4L IS USM" {
+		t.Fatalf("Invalid lens: %v", xmp["aux:Lens"])
+	}
+	if value, ok := xmp.Get("exif:DateTimeOriginal"); !ok || value != "2021-05-01T12:34:56.00+02:00" {
+		t.Fatalf("Invalid date: %v", xmp["exif:DateTimeOriginal"])
+	}
+	if value, ok := xmp.Get("exif:ISOSpeedRatings"); !ok || value != "400" {
+		t.Fatalf("Invalid ISO: %v", xmp["exif:ISOSpeedRatings"])
+	}
+	if subject := xmp["dc:subject"]; len(subject) != 2 || subject[0] != "travel" || subject[1] != "mountains" {
+		t.Fatalf("Invalid subject: %v", subject)
+	}
+}
+
+func TestReadingXmpBeforeExif(t *testing.T) {
+	xmpSegment := jpegSegment(exifDataMarker, append([]byte(xmpIdentifier+"\x00"), []byte(testXmpPacket)...))
+	tiff := buildTiff([]tiffEntry{asciiEntry(0x010f, "TestMake")})
+	exifSegment := jpegSegment(exifDataMarker, append([]byte{'E', 'x', 'i', 'f', 0, 0}, tiff...))
+
+	file, err := OpenExifFileIo(writeTempFile(t, ".jpg", buildJpegWithSegments(xmpSegment, exifSegment)))
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+
+	metadata, err := ReadMetadata(file)
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	if tag, ok := TagsAsMap(metadata.Tags)[cameraMake]; !ok || tag.Value.(string) != "TestMake" {
+		t.Fatalf("Failed to read Exif after XMP segment")
+	}
+	if value, ok := metadata.Xmp.Get("aux:Lens"); !ok || value != "EF24-105mm f/4L IS USM" {
+		t.Fatalf("Failed to read XMP lens: %v", metadata.Xmp)
+	}
+}
+
+func TestReadingTruncatedXmpSegment(t *testing.T) {
+	// identifier without terminating zero leaves no room for XMP packet
+	xmpSegment := jpegSegment(exifDataMarker, []byte(xmpIdentifier))
+	file, err := OpenExifFileIo(writeTempFile(t, ".jpg", buildJpegWithSegments(xmpSegment)))
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer func() { file.Close() }()
+
+	_, err = ReadMetadata(file)
+	if err == nil {
+		t.Fatalf("Truncated XMP segment must be reported")
+	}
+}
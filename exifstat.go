@@ -1,12 +1,15 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"strings"
 	"sync"
 
 	"github.com/jessevdk/go-flags"
+	"github.com/uaraven/exif-stat/exif"
 	"github.com/uaraven/exif-stat/logger"
 )
 
@@ -20,6 +23,9 @@ var (
 		FastFile      bool   `long:"fast-io" description:"Use memory-mapped io. May be unstable with network paths"`
 		WriteFileName bool   `short:"f" long:"file-name" description:"Include file name in the output"`
 		ExtendFlash   bool   `long:"extend-flash" description:"Detailed flash status"`
+		XmpPrecedence string `long:"xmp-precedence" description:"Metadata which wins when both XMP sidecar and image contain a value" choice:"embedded" choice:"sidecar" default:"sidecar"`
+		WriteIptc     bool   `long:"iptc" description:"Include IPTC keywords, caption, byline and copyright in the output"`
+		MpImages      bool   `long:"mp-images" description:"Treat every image of multi-picture (MPO) files as a separate record"`
 	}{}
 )
 
@@ -39,6 +45,42 @@ func csvHeader() string {
 	sb.WriteString(",LensMake")
 	sb.WriteString(",LensModel")
 	sb.WriteString(",MPix")
+	sb.WriteString(",Latitude")
+	sb.WriteString(",Longitude")
+	sb.WriteString(",Altitude")
+	sb.WriteString(",GpsTime")
+	sb.WriteString(",GpsDirection")
+	sb.WriteString(",MediaType")
+	sb.WriteString(",Rating")
+	sb.WriteString(",Sources")
+	sb.WriteString(",ColorSpace")
+	sb.WriteString(",Subsampling")
+	sb.WriteString(",JpegMode")
+	sb.WriteString(",JpegQuality")
+	sb.WriteString(",JpegTables")
+	sb.WriteString(",SerialNumber")
+	sb.WriteString(",LensSerialNumber")
+	sb.WriteString(",ShutterCount")
+	sb.WriteString(",FocusMode")
+	sb.WriteString(",QualitySetting")
+	sb.WriteString(",FocusDistance")
+	sb.WriteString(",ShutterType")
+	sb.WriteString(",PictureStyle")
+	sb.WriteString(",DynamicRange")
+	sb.WriteString(",GrainEffect")
+	sb.WriteString(",SceneMode")
+	sb.WriteString(",DriveMode")
+	sb.WriteString(",Stabilization")
+	sb.WriteString(",LivePhotoID")
+	if options.WriteIptc {
+		sb.WriteString(",Keywords")
+		sb.WriteString(",Caption")
+		sb.WriteString(",Byline")
+		sb.WriteString(",Copyright")
+	}
+	if options.MpImages {
+		sb.WriteString(",ImageIndex")
+	}
 	if options.WriteFileName {
 		sb.WriteString(",FileName")
 	}
@@ -63,6 +105,50 @@ func (ei *ExifInfo) asCsv() string {
 	sb.WriteString(fmt.Sprintf(",\"%s\"", strings.TrimSpace(ei.LensModel)))
 	mpix := float64(ei.Width*ei.Height) / 1000000.0
 	sb.WriteString(fmt.Sprintf(",\"%.1f\"", mpix))
+	if ei.HasGpsPosition {
+		sb.WriteString(fmt.Sprintf(",\"%.6f\"", ei.GpsLatitude))
+		sb.WriteString(fmt.Sprintf(",\"%.6f\"", ei.GpsLongitude))
+		sb.WriteString(fmt.Sprintf(",\"%.1f\"", ei.GpsAltitude))
+	} else {
+		sb.WriteString(",\"\",\"\",\"\"")
+	}
+	sb.WriteString(fmt.Sprintf(",\"%s\"", ei.GpsTime))
+	if ei.HasGpsDirection {
+		sb.WriteString(fmt.Sprintf(",\"%.1f\"", ei.GpsDirection))
+	} else {
+		sb.WriteString(",\"\"")
+	}
+	sb.WriteString(fmt.Sprintf(",\"%s\"", ei.MediaType))
+	sb.WriteString(fmt.Sprintf(",\"%d\"", ei.Rating))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", ei.sourcesAsString()))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", csvEscape(ei.ColorSpace)))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", ei.Subsampling))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", ei.JpegMode))
+	sb.WriteString(fmt.Sprintf(",\"%d\"", ei.JpegQuality))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", ei.JpegTables))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", csvEscape(ei.SerialNumber)))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", csvEscape(ei.LensSerialNumber)))
+	sb.WriteString(fmt.Sprintf(",\"%d\"", ei.ShutterCount))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", ei.FocusMode))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", ei.QualitySetting))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", focusDistanceString(ei.FocusDistance)))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", ei.ShutterType))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", ei.PictureStyle))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", ei.DynamicRange))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", ei.GrainEffect))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", ei.SceneMode))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", ei.DriveMode))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", ei.Stabilization))
+	sb.WriteString(fmt.Sprintf(",\"%s\"", csvEscape(ei.LivePhotoID)))
+	if options.WriteIptc {
+		sb.WriteString(fmt.Sprintf(",\"%s\"", csvEscape(strings.Join(ei.Keywords, ";"))))
+		sb.WriteString(fmt.Sprintf(",\"%s\"", csvEscape(ei.Caption)))
+		sb.WriteString(fmt.Sprintf(",\"%s\"", csvEscape(ei.Byline)))
+		sb.WriteString(fmt.Sprintf(",\"%s\"", csvEscape(ei.Copyright)))
+	}
+	if options.MpImages {
+		sb.WriteString(fmt.Sprintf(",\"%d\"", ei.ImageIndex))
+	}
 	if options.WriteFileName {
 		sb.WriteString(fmt.Sprintf(",\"%s\"", ei.FileName))
 	}
@@ -70,17 +156,36 @@ func (ei *ExifInfo) asCsv() string {
 	return sb.String()
 }
 
+// focus distance in metres, empty if unknown
+func focusDistanceString(distance float64) string {
+	if math.IsInf(distance, 1) {
+		return "inf"
+	}
+	if distance == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", distance)
+}
+
 func (ei *ExifInfo) isValidExif() bool {
+	if ei.MediaType == mediaTypeVideo { // video files rarely have exposure information
+		return (len(ei.Make) > 0 || len(ei.Model) > 0) && len(ei.CreateTime) > 0
+	}
 	return len(ei.Make) > 0 && len(ei.Model) > 0 && ei.FNumber.Denominator != 0 && ei.ExposureTime.Denominator != 0 && ei.FocalLength.Denominator != 0 && len(ei.CreateTime) > 0
 }
 
-func parseExif(wg *sync.WaitGroup, paths chan string, exifs chan *ExifInfo) {
+func parseExif(wg *sync.WaitGroup, images chan ImageFile, exifs chan *ExifInfo) {
 	defer close(exifs)
 	defer wg.Done()
-	for path := range paths {
-		exif, err := ExtractExif(path, options.FastFile)
+	for image := range images {
+		path := image.Path
+		exifInfos, err := ExtractImagesExif(path, image.SidecarPath, options.FastFile, options.MpImages)
 		if err == nil {
-			exifs <- exif
+			for _, exifInfo := range exifInfos {
+				exifs <- exifInfo
+			}
+		} else if errors.Is(err, exif.ErrCompressedExif) { // file has Exif, but it cannot be read, always report it
+			logger.Verbose(0, fmt.Sprintf("\nCannot read EXIF from '%s': %s", path, err))
 		} else {
 			logger.Verbose(1, fmt.Sprintf("\nFailed to extract EXIF from '%s': %s", path, err))
 		}
@@ -103,6 +208,11 @@ func writeCsv(wg *sync.WaitGroup, exifs chan *ExifInfo) {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == thumbnailsCommand {
+		runThumbnails(os.Args[2:])
+		return
+	}
+
 	_, err := flags.Parse(options)
 
 	if err != nil {
@@ -115,12 +225,12 @@ func main() {
 	}
 
 	var wg sync.WaitGroup
-	paths := make(chan string)
+	images := make(chan ImageFile)
 	exifs := make(chan *ExifInfo)
 
 	wg.Add(3)
-	go ListImages(options.Args.FolderPath, &wg, paths)
-	go parseExif(&wg, paths, exifs)
+	go ListImages(options.Args.FolderPath, &wg, images)
+	go parseExif(&wg, images, exifs)
 	go writeCsv(&wg, exifs)
 
 	wg.Wait()
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCsvColumns(t *testing.T) {
+	header := strings.Split(strings.TrimSpace(csvHeader()), ",")
+	row := strings.Split(strings.TrimSpace((&ExifInfo{Make: "Make", Model: "Model"}).asCsv()), ",")
+	if len(header) != len(row) {
+		t.Fatalf("Header has %d columns, row has %d", len(header), len(row))
+	}
+	// columns are read by position, new columns must never shift the existing ones
+	positions := map[string]int{
+		"LensModel":    12,
+		"MPix":         13,
+		"Latitude":     14,
+		"MediaType":    19,
+		"Rating":       20,
+		"Sources":      21,
+		"ColorSpace":   22,
+		"JpegTables":   26,
+		"SerialNumber": 27,
+		"LivePhotoID":  40,
+	}
+	for column, position := range positions {
+		if header[position] != column {
+			t.Errorf("Column %s moved from position %d, found %s", column, position, header[position])
+		}
+	}
+}
+
+func TestCsvGpsDirection(t *testing.T) {
+	tests := []struct {
+		exifInfo *ExifInfo
+		expected string
+	}{
+		{&ExifInfo{}, `""`},
+		{&ExifInfo{HasGpsDirection: true, GpsDirection: 0}, `"0.0"`},
+		{&ExifInfo{HasGpsDirection: true, GpsDirection: 271.25}, `"271.2"`},
+	}
+	header := strings.Split(strings.TrimSpace(csvHeader()), ",")
+	for _, test := range tests {
+		row := strings.Split(strings.TrimSpace(test.exifInfo.asCsv()), ",")
+		for i, column := range header {
+			if column == "GpsDirection" && row[i] != test.expected {
+				t.Errorf("GpsDirection %s != %s", row[i], test.expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+const (
+	tagFujiQuality             = "8769/927c/1000"
+	tagFujiSaturation          = "8769/927c/1003"
+	tagFujiGrainEffect         = "8769/927c/1047"
+	tagFujiGrainEffectSize     = "8769/927c/104c"
+	tagFujiShutterType         = "8769/927c/1050"
+	tagFujiDynamicRange        = "8769/927c/1400"
+	tagFujiFilmMode            = "8769/927c/1401"
+	tagFujiDynamicRangeSetting = "8769/927c/1402"
+	tagFujiDevelopmentRange    = "8769/927c/1403"
+)
+
+var fujiFilmModes = map[uint32]string{
+	0x000: "Provia",
+	0x100: "Studio Portrait",
+	0x110: "Studio Portrait Enhanced Saturation",
+	0x120: "Astia",
+	0x130: "Studio Portrait Increased Sharpness",
+	0x200: "Velvia",
+	0x300: "Studio Portrait Ex",
+	0x400: "Velvia",
+	0x500: "Pro Neg. Std",
+	0x501: "Pro Neg. Hi",
+	0x600: "Classic Chrome",
+	0x700: "Eterna",
+	0x800: "Classic Negative",
+	0x900: "Eterna Bleach Bypass",
+	0xa00: "Nostalgic Negative",
+	0xb00: "Reala Ace",
+}
+
+// monochrome film simulations are stored in saturation tag
+var fujiMonochromeModes = map[uint32]string{
+	0x300: "Monochrome",
+	0x301: "Monochrome + R Filter",
+	0x302: "Monochrome + Ye Filter",
+	0x303: "Monochrome + G Filter",
+	0x310: "Sepia",
+	0x500: "Acros",
+	0x501: "Acros + R Filter",
+	0x502: "Acros + Ye Filter",
+	0x503: "Acros + G Filter",
+}
+
+var fujiDynamicRangeSettings = map[uint32]string{
+	0x000: "Auto",
+	0x001: "Manual",
+	0x100: "Standard (100%)",
+	0x200: "Wide1 (230%)",
+	0x201: "Wide2 (400%)",
+}
+
+var fujiDynamicRanges = map[uint32]string{
+	1: "Standard",
+	3: "Wide",
+}
+
+var fujiGrainEffects = map[uint32]string{
+	0:  "Off",
+	32: "Weak",
+	64: "Strong",
+}
+
+var fujiGrainSizes = map[uint32]string{
+	16: "Small",
+	32: "Large",
+}
+
+var fujiShutterTypes = map[uint32]string{
+	0: "Mechanical",
+	1: "Electronic",
+	2: "Electronic (long shutter speed)",
+	3: "Electronic Front Curtain",
+}
+
+// extractFujifilm decodes film simulation, dynamic range, grain effect, shutter type and quality setting from Fujifilm
+// maker notes
+func extractFujifilm(tagMap map[string]exif.Tag, exifInfo *ExifInfo) {
+	if !strings.HasPrefix(strings.ToUpper(exifInfo.Make), "FUJIFILM") {
+		return
+	}
+	if saturation, ok := tagNumber(tagMap, tagFujiSaturation); ok && fujiMonochromeModes[saturation] != "" {
+		exifInfo.PictureStyle = fujiMonochromeModes[saturation]
+	} else if filmMode, ok := tagNumber(tagMap, tagFujiFilmMode); ok {
+		exifInfo.PictureStyle = fujiFilmModes[filmMode]
+	}
+	exifInfo.DynamicRange = fujiDynamicRange(tagMap)
+	if roughness, ok := tagNumber(tagMap, tagFujiGrainEffect); ok {
+		exifInfo.GrainEffect = fujiGrainEffects[roughness]
+		if size, ok := tagNumber(tagMap, tagFujiGrainEffectSize); ok && roughness != 0 && fujiGrainSizes[size] != "" {
+			exifInfo.GrainEffect += ", " + fujiGrainSizes[size]
+		}
+	}
+	if shutterType, ok := tagNumber(tagMap, tagFujiShutterType); ok {
+		exifInfo.ShutterType = fujiShutterTypes[shutterType]
+	}
+	exifInfo.QualitySetting = qualitySetting(tagMap, tagFujiQuality)
+}
+
+// development dynamic range is the actual percentage, setting and dynamic range mode are used for the older cameras
+func fujiDynamicRange(tagMap map[string]exif.Tag) string {
+	if developmentRange, ok := tagNumber(tagMap, tagFujiDevelopmentRange); ok && developmentRange != 0 {
+		return fmt.Sprintf("DR%d", developmentRange)
+	}
+	if setting, ok := tagNumber(tagMap, tagFujiDynamicRangeSetting); ok && fujiDynamicRangeSettings[setting] != "" {
+		return fujiDynamicRangeSettings[setting]
+	}
+	if dynamicRange, ok := tagNumber(tagMap, tagFujiDynamicRange); ok {
+		return fujiDynamicRanges[dynamicRange]
+	}
+	return ""
+}
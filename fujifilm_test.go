@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+// fujiMakerNote creates Fujifilm maker notes: header, offset of the IFD and little endian IFD with offsets relative to
+// the start of maker notes
+func fujiMakerNote(entries ...tiffEntry) []byte {
+	return buildMakerNote(binary.LittleEndian, 0, []byte("FUJIFILM\x0c\x00\x00\x00"), entries...)
+}
+
+func TestExtractFujifilm(t *testing.T) {
+	tests := []struct {
+		name         string
+		entries      []tiffEntry
+		pictureStyle string
+		dynamicRange string
+		grainEffect  string
+		shutterType  string
+		quality      string
+	}{
+		{"film simulation", []tiffEntry{
+			shortEntry(exif.FujiFilmModeTagID, 0x600),
+			shortEntry(exif.FujiSaturationTagID, 0),
+		}, "Classic Chrome", "", "", "", ""},
+		{"monochrome", []tiffEntry{
+			shortEntry(exif.FujiSaturationTagID, 0x501),
+			shortEntry(exif.FujiFilmModeTagID, 0),
+		}, "Acros + R Filter", "", "", "", ""},
+		{"development dynamic range", []tiffEntry{
+			shortEntry(exif.FujiDynamicRangeTagID, 1),
+			shortEntry(exif.FujiDynamicRangeSettingTagID, 0),
+			shortEntry(exif.FujiDevelopmentDynamicRangeID, 200),
+		}, "", "DR200", "", "", ""},
+		{"dynamic range setting", []tiffEntry{
+			shortEntry(exif.FujiDynamicRangeTagID, 3),
+			shortEntry(exif.FujiDynamicRangeSettingTagID, 0x200),
+		}, "", "Wide1 (230%)", "", "", ""},
+		{"grain effect", []tiffEntry{
+			longEntry(exif.FujiGrainEffectRoughnessTagID, 64),
+			longEntry(exif.FujiGrainEffectSizeTagID, 16),
+		}, "", "", "Strong, Small", "", ""},
+		{"shutter type and quality", []tiffEntry{
+			asciiEntry(exif.FujiQualityTagID, "NORMAL "),
+			shortEntry(exif.FujiShutterTypeTagID, 1),
+		}, "", "", "", "Electronic", "Normal"},
+	}
+	for _, test := range tests {
+		entries := test.entries
+		exifInfo := readTestExif(t, buildMakerNoteJpeg("FUJIFILM", "X-T4", func(offset uint32) []byte {
+			return fujiMakerNote(entries...)
+		}))
+		if exifInfo.PictureStyle != test.pictureStyle {
+			t.Errorf("%s: picture style '%s' != '%s'", test.name, exifInfo.PictureStyle, test.pictureStyle)
+		}
+		if exifInfo.DynamicRange != test.dynamicRange {
+			t.Errorf("%s: dynamic range '%s' != '%s'", test.name, exifInfo.DynamicRange, test.dynamicRange)
+		}
+		if exifInfo.GrainEffect != test.grainEffect {
+			t.Errorf("%s: grain effect '%s' != '%s'", test.name, exifInfo.GrainEffect, test.grainEffect)
+		}
+		if exifInfo.ShutterType != test.shutterType {
+			t.Errorf("%s: shutter type '%s' != '%s'", test.name, exifInfo.ShutterType, test.shutterType)
+		}
+		if exifInfo.QualitySetting != test.quality {
+			t.Errorf("%s: quality setting '%s' != '%s'", test.name, exifInfo.QualitySetting, test.quality)
+		}
+	}
+}
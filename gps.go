@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+const (
+	tagGpsLatitudeRef  = "8825/0001"
+	tagGpsLatitude     = "8825/0002"
+	tagGpsLongitudeRef = "8825/0003"
+	tagGpsLongitude    = "8825/0004"
+	tagGpsAltitudeRef  = "8825/0005"
+	tagGpsAltitude     = "8825/0006"
+	tagGpsTimeStamp    = "8825/0007"
+	tagGpsImgDirection = "8825/0011"
+	tagGpsDateStamp    = "8825/001d"
+)
+
+// converts degrees, minutes and seconds into signed decimal degrees. South and West references produce negative values
+func gpsCoordinate(dms []exif.Rational, ref string) (float64, bool) {
+	if len(dms) < 3 || dms[0].Denominator == 0 {
+		return 0, false
+	}
+	value := dms[0].AsFloat()
+	if dms[1].Denominator != 0 {
+		value += dms[1].AsFloat() / 60.0
+	}
+	if dms[2].Denominator != 0 {
+		value += dms[2].AsFloat() / 3600.0
+	}
+	ref = strings.ToUpper(strings.TrimSpace(ref))
+	if ref == "S" || ref == "W" {
+		value = -value
+	}
+	return value, true
+}
+
+func tagString(tagMap map[string]exif.Tag, path string) string {
+	if tag, ok := tagMap[path]; ok {
+		if value, ok := tag.Value.(string); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+func tagRationals(tagMap map[string]exif.Tag, path string) []exif.Rational {
+	if tag, ok := tagMap[path]; ok {
+		if value, ok := tag.Value.([]exif.Rational); ok {
+			return value
+		}
+	}
+	return nil
+}
+
+// combines GPS date stamp and time stamp into a single UTC timestamp. Returns only time if date stamp is missing
+func gpsTimestamp(date string, hms []exif.Rational) string {
+	if len(hms) < 3 || hms[0].Denominator == 0 || hms[1].Denominator == 0 || hms[2].Denominator == 0 {
+		return ""
+	}
+	timeValue := fmt.Sprintf("%02d:%02d:%02d", int(hms[0].AsFloat()), int(hms[1].AsFloat()), int(hms[2].AsFloat()))
+	if len(date) == 0 {
+		return timeValue
+	}
+	tm, err := parseExifFullTimestamp(date + " " + timeValue)
+	if err != nil {
+		return timeValue
+	}
+	return tm.Format(time.RFC3339)
+}
+
+// extractGps decodes GPS IFD tags into signed decimal values of latitude, longitude, altitude and image direction
+func extractGps(tagMap map[string]exif.Tag, exifInfo *ExifInfo) {
+	latitude, latOk := gpsCoordinate(tagRationals(tagMap, tagGpsLatitude), tagString(tagMap, tagGpsLatitudeRef))
+	longitude, lonOk := gpsCoordinate(tagRationals(tagMap, tagGpsLongitude), tagString(tagMap, tagGpsLongitudeRef))
+	if latOk && lonOk {
+		exifInfo.HasGpsPosition = true
+		exifInfo.GpsLatitude = latitude
+		exifInfo.GpsLongitude = longitude
+	}
+	if altitude := tagRationals(tagMap, tagGpsAltitude); len(altitude) > 0 && altitude[0].Denominator != 0 {
+		exifInfo.GpsAltitude = altitude[0].AsFloat()
+		if tag, ok := tagMap[tagGpsAltitudeRef]; ok {
+			if ref, ok := tag.Value.([]byte); ok && len(ref) > 0 && ref[0] == 1 { // 1 means below sea level
+				exifInfo.GpsAltitude = -exifInfo.GpsAltitude
+			}
+		}
+	}
+	if direction := tagRationals(tagMap, tagGpsImgDirection); len(direction) > 0 && direction[0].Denominator != 0 {
+		exifInfo.HasGpsDirection = true
+		exifInfo.GpsDirection = direction[0].AsFloat()
+	}
+	exifInfo.GpsTime = gpsTimestamp(tagString(tagMap, tagGpsDateStamp), tagRationals(tagMap, tagGpsTimeStamp))
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+// applyIptc copies keywords and credits from IPTC datasets
+func applyIptc(iptc exif.IptcProperties, exifInfo *ExifInfo) {
+	if len(iptc) == 0 {
+		return
+	}
+	exifInfo.Keywords = appendKeywords(exifInfo.Keywords, iptc[exif.IptcKeywords]...)
+	if value, ok := iptc.Get(exif.IptcCaption); ok {
+		exifInfo.Caption = strings.TrimSpace(value)
+	}
+	if value, ok := iptc.Get(exif.IptcByline); ok {
+		exifInfo.Byline = strings.TrimSpace(value)
+	}
+	if value, ok := iptc.Get(exif.IptcCopyright); ok {
+		exifInfo.Copyright = strings.TrimSpace(value)
+	}
+}
+
+// appendKeywords adds non-empty keywords which are not in the list yet. The same keywords are often stored both in
+// IPTC and XMP
+func appendKeywords(keywords []string, values ...string) []string {
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) == 0 {
+			continue
+		}
+		found := false
+		for _, keyword := range keywords {
+			if keyword == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			keywords = append(keywords, value)
+		}
+	}
+	return keywords
+}
+
+// free-form IPTC text may contain quotes, which must be doubled inside quoted CSV values
+func csvEscape(value string) string {
+	return strings.ReplaceAll(value, "\"", "\"\"")
+}
@@ -25,9 +25,45 @@ type ExifInfo struct {
 	ExposureCompensation exif.SignedRational
 	LensMake             string
 	LensModel            string
+	SerialNumber         string
+	LensSerialNumber     string
+	ShutterCount         uint32
+	FocusMode            string
+	QualitySetting       string
+	FocusDistance        float64
+	ShutterType          string
+	PictureStyle         string
+	DynamicRange         string
+	GrainEffect          string
+	SceneMode            string
+	DriveMode            string
+	Stabilization        string
+	LivePhotoID          string
 	Width                uint32
 	Height               uint32
-	FileName             string
+	HasGpsPosition       bool
+	GpsLatitude          float64
+	GpsLongitude         float64
+	GpsAltitude          float64
+	HasGpsDirection      bool
+	GpsDirection         float64
+	GpsTime              string
+	MediaType            string
+	Rating               int
+	ColorSpace           string
+	Subsampling          string
+	JpegMode             string
+	JpegQuality          int
+	JpegTables           string
+	Keywords             []string
+	Caption              string
+	Byline               string
+	Copyright            string
+	// Index of the image in Multi-Picture Format file, 0 for the primary image
+	ImageIndex int
+	// Sources of the values which were not read from Exif, keyed by CSV column name
+	Sources  map[string]string
+	FileName string
 }
 
 func (ei *ExifInfo) toString() string {
@@ -44,6 +80,14 @@ func (ei *ExifInfo) toString() string {
 	sb.WriteString(fmt.Sprintf("Exposure program: %s\n", ei.ExposureProgram))
 	sb.WriteString(fmt.Sprintf("Lens make: %s\n", ei.LensMake))
 	sb.WriteString(fmt.Sprintf("Lens model: %s\n", ei.LensModel))
+	if ei.HasGpsPosition {
+		sb.WriteString(fmt.Sprintf("GPS position: %f, %f\n", ei.GpsLatitude, ei.GpsLongitude))
+		sb.WriteString(fmt.Sprintf("GPS altitude: %f\n", ei.GpsAltitude))
+	}
+	if ei.HasGpsDirection {
+		sb.WriteString(fmt.Sprintf("GPS direction: %f\n", ei.GpsDirection))
+	}
+	sb.WriteString(fmt.Sprintf("GPS time: %s\n", ei.GpsTime))
 	return sb.String()
 }
 
@@ -64,6 +108,38 @@ func (ei *ExifInfo) toMap() map[string]interface{} {
 		"Height":               ei.Height,
 		"LensMake":             ei.LensMake,
 		"LensModel":            ei.LensModel,
+		"SerialNumber":         ei.SerialNumber,
+		"LensSerialNumber":     ei.LensSerialNumber,
+		"ShutterCount":         ei.ShutterCount,
+		"FocusMode":            ei.FocusMode,
+		"QualitySetting":       ei.QualitySetting,
+		"FocusDistance":        ei.FocusDistance,
+		"ShutterType":          ei.ShutterType,
+		"PictureStyle":         ei.PictureStyle,
+		"DynamicRange":         ei.DynamicRange,
+		"GrainEffect":          ei.GrainEffect,
+		"SceneMode":            ei.SceneMode,
+		"DriveMode":            ei.DriveMode,
+		"Stabilization":        ei.Stabilization,
+		"LivePhotoID":          ei.LivePhotoID,
+		"GpsLatitude":          ei.GpsLatitude,
+		"GpsLongitude":         ei.GpsLongitude,
+		"GpsAltitude":          ei.GpsAltitude,
+		"GpsDirection":         ei.GpsDirection,
+		"GpsTime":              ei.GpsTime,
+		"MediaType":            ei.MediaType,
+		"Rating":               ei.Rating,
+		"ColorSpace":           ei.ColorSpace,
+		"Subsampling":          ei.Subsampling,
+		"JpegMode":             ei.JpegMode,
+		"JpegQuality":          ei.JpegQuality,
+		"JpegTables":           ei.JpegTables,
+		"Sources":              ei.sourcesAsString(),
+		"Keywords":             strings.Join(ei.Keywords, ";"),
+		"Caption":              ei.Caption,
+		"Byline":               ei.Byline,
+		"Copyright":            ei.Copyright,
+		"ImageIndex":           ei.ImageIndex,
 	}
 }
 
@@ -90,6 +166,15 @@ const (
 	tagNikonIso             = "8769/927c/0002"
 	tagLensMake             = "8769/a433"
 	tagLensModel            = "8769/a434"
+	tagRating               = "4746"
+	tagBodySerialNumber     = "8769/a431"
+	tagPanasonicRawWidth    = "0002"
+	tagPanasonicRawHeight   = "0003"
+	tagPanasonicRawTop      = "0004"
+	tagPanasonicRawLeft     = "0005"
+	tagPanasonicRawBottom   = "0006"
+	tagPanasonicRawRight    = "0007"
+	tagPanasonicRawIso      = "0017"
 )
 
 var exifFlashValues = map[uint]string{
@@ -243,14 +328,152 @@ var extractors = map[string]tagValueExtractor{
 	tagLensModel: func(tag exif.Tag, exifInfo *ExifInfo) {
 		exifInfo.LensModel = tag.Value.(string)
 	},
+	tagBodySerialNumber: func(tag exif.Tag, exifInfo *ExifInfo) {
+		exifInfo.SerialNumber = strings.TrimSpace(tag.Value.(string))
+	},
+	tagRating: func(tag exif.Tag, exifInfo *ExifInfo) {
+		// rating is a short, but darktable writes it as signed long and other software may use other integer types
+		if rating, ok := signedNumberValue(tag); ok {
+			exifInfo.Rating = int(rating)
+		}
+	},
 }
 
 func extractNikonIso(tag exif.Tag, exifInfo *ExifInfo) {
-	if tag.DataType != exif.TypeUnsignedShort { // sometimes there is TypeUndefined and all zeroes here
+	values, ok := tag.Value.([]uint16)
+	if !ok || len(values) < 2 { // sometimes there is TypeUndefined and all zeroes here
 		logger.Verbose(2, fmt.Sprintf("\nUnexpected data type in tag %v in exifinfo: %v", tag, exifInfo))
 		return
 	}
-	exifInfo.Iso = tag.Value.([]uint16)[1]
+	exifInfo.Iso = values[1]
+}
+
+func tagShort(tagMap map[string]exif.Tag, path string) uint16 {
+	if tag, ok := tagMap[path]; ok {
+		if value, ok := tag.Value.([]uint16); ok && len(value) > 0 {
+			return value[0]
+		}
+	}
+	return 0
+}
+
+// tagNumber returns the first value of the numeric tag of any unsigned integer type
+func tagNumber(tagMap map[string]exif.Tag, path string) (uint32, bool) {
+	if tag, ok := tagMap[path]; ok {
+		return numberValue(tag)
+	}
+	return 0, false
+}
+
+// tagSignedNumber returns the first value of the numeric tag of any integer type
+func tagSignedNumber(tagMap map[string]exif.Tag, path string) (int64, bool) {
+	if tag, ok := tagMap[path]; ok {
+		return signedNumberValue(tag)
+	}
+	return 0, false
+}
+
+func numberValue(tag exif.Tag) (uint32, bool) {
+	switch value := tag.Value.(type) {
+	case []byte:
+		if len(value) > 0 {
+			return uint32(value[0]), true
+		}
+	case []uint16:
+		if len(value) > 0 {
+			return uint32(value[0]), true
+		}
+	case []uint32:
+		if len(value) > 0 {
+			return value[0], true
+		}
+	}
+	return 0, false
+}
+
+func signedNumberValue(tag exif.Tag) (int64, bool) {
+	switch value := tag.Value.(type) {
+	case []int8:
+		if len(value) > 0 {
+			return int64(value[0]), true
+		}
+	case []int16:
+		if len(value) > 0 {
+			return int64(value[0]), true
+		}
+	case []int32:
+		if len(value) > 0 {
+			return int64(value[0]), true
+		}
+	default:
+		if number, ok := numberValue(tag); ok {
+			return int64(number), true
+		}
+	}
+	return 0, false
+}
+
+// JPEG quality setting stored as upper-case text is converted to the names used by the other vendors
+var qualitySettingNames = map[string]string{
+	"BASIC":  "Basic",
+	"NORM":   "Normal",
+	"NORMAL": "Normal",
+	"FINE":   "Fine",
+}
+
+// qualitySetting returns the name of the JPEG quality setting stored as text, unknown values are returned as is
+func qualitySetting(tagMap map[string]exif.Tag, path string) string {
+	quality := strings.TrimSpace(tagString(tagMap, path))
+	if name, ok := qualitySettingNames[strings.ToUpper(quality)]; ok {
+		return name
+	}
+	return quality
+}
+
+// Panasonic RW2 files keep ISO and sensor dimensions in Panasonic-specific tags of IFD0
+func extractPanasonicRaw(tagMap map[string]exif.Tag, exifInfo *ExifInfo) {
+	cameraMake := strings.ToUpper(exifInfo.Make)
+	if !strings.HasPrefix(cameraMake, "PANASONIC") && !strings.HasPrefix(cameraMake, "LEICA") {
+		return
+	}
+	if iso := tagShort(tagMap, tagPanasonicRawIso); iso != 0 && exifInfo.Iso == 0 {
+		exifInfo.Iso = iso
+	}
+	if exifInfo.Width != 0 && exifInfo.Height != 0 {
+		return
+	}
+	// sensor borders give the size of the image area, full sensor size is used as a fallback
+	top, left := tagShort(tagMap, tagPanasonicRawTop), tagShort(tagMap, tagPanasonicRawLeft)
+	bottom, right := tagShort(tagMap, tagPanasonicRawBottom), tagShort(tagMap, tagPanasonicRawRight)
+	if right > left && bottom > top {
+		exifInfo.Width = uint32(right - left)
+		exifInfo.Height = uint32(bottom - top)
+	} else if width, height := tagShort(tagMap, tagPanasonicRawWidth), tagShort(tagMap, tagPanasonicRawHeight); width != 0 && height != 0 {
+		exifInfo.Width = uint32(width)
+		exifInfo.Height = uint32(height)
+	}
+}
+
+// JPEG frame header always contains the real size of the image, it is used when Exif does not report image size
+func extractJpegFrame(frame *exif.JpegFrame, exifInfo *ExifInfo) {
+	if frame == nil {
+		return
+	}
+	if exifInfo.Width == 0 || exifInfo.Height == 0 {
+		exifInfo.Width = uint32(frame.Width)
+		exifInfo.Height = uint32(frame.Height)
+	}
+	exifInfo.Subsampling = frame.Subsampling()
+	exifInfo.JpegMode = frame.Mode
+}
+
+// estimated quality and known encoder tables help to find images re-saved by software
+func extractJpegQuality(tables exif.QuantizationTables, exifInfo *ExifInfo) {
+	if len(tables) == 0 {
+		return
+	}
+	exifInfo.JpegQuality, _ = exif.EstimateJpegQuality(tables)
+	exifInfo.JpegTables = exif.IdentifyQuantTables(tables)
 }
 
 func parseExifFullTimestamp(timestamp string) (*time.Time, error) {
@@ -302,37 +525,20 @@ func parseExifFullTimestamp(timestamp string) (*time.Time, error) {
 	return &res, nil
 }
 
-// ExtractExif parses image file with a given path and extracts exif information
-func ExtractExif(imageFilePath string, mmap bool) (exifInfo *ExifInfo, err error) {
-	exifInfo = &ExifInfo{
-		FileName: imageFilePath,
-	}
-	defer func() {
-		state := recover()
-		if state != nil {
-			logger.Verbose(2, fmt.Sprintf("Faulted while reading %s: %v", imageFilePath, state))
-			exifInfo = nil
-			err = fmt.Errorf("Faulted while reading %s: %v", imageFilePath, state)
-		}
-	}()
-	var f exif.File
+func openImageFile(imageFilePath string, mmap bool) (exif.File, error) {
 	if mmap {
-		f, err = exif.OpenExifFileMMap(imageFilePath)
-	} else {
-		f, err = exif.OpenExifFileIo(imageFilePath)
+		return exif.OpenExifFileMMap(imageFilePath)
 	}
-	if err != nil {
-		return nil, err
-	}
-	defer func() { f.Close() }()
+	return exif.OpenExifFileIo(imageFilePath)
+}
 
-	tags, err := exif.ReadExifTags(f)
-	if err != nil {
-		return nil, err
+// converts metadata read from the file and its XMP sidecar into ExifInfo
+func metadataToExifInfo(metadata *exif.Metadata, sidecar exif.XmpProperties, exifInfo *ExifInfo) *ExifInfo {
+	tagMap := exif.TagsAsMap(metadata.Tags)
+	if metadata.Format == exif.FormatVideo {
+		exifInfo.MediaType = mediaTypeVideo
 	}
 
-	tagMap := exif.TagsAsMap(tags)
-
 	for path, extractor := range extractors {
 		tag, ok := tagMap[path]
 		if ok {
@@ -344,7 +550,96 @@ func ExtractExif(imageFilePath string, mmap bool) (exifInfo *ExifInfo, err error
 			extractNikonIso(tag, exifInfo)
 		}
 	}
+	extractPanasonicRaw(tagMap, exifInfo)
+	extractNikon(tagMap, exifInfo)
+	extractCanon(tagMap, exifInfo)
+	extractSony(tagMap, exifInfo)
+	extractFujifilm(tagMap, exifInfo)
+	extractOlympus(tagMap, exifInfo)
+	extractPanasonic(tagMap, exifInfo)
+	extractPentax(tagMap, exifInfo)
+	extractApple(tagMap, exifInfo)
+	extractGps(tagMap, exifInfo)
+	extractColorSpace(tagMap, metadata.IccProfile, exifInfo)
+	extractJpegFrame(metadata.Frame, exifInfo)
+	extractJpegQuality(metadata.QuantizationTables, exifInfo)
+	applyXmp(metadata.Xmp, exifInfo)
+	applyIptc(metadata.Iptc, exifInfo)
+	applySidecar(sidecar, options.XmpPrecedence, exifInfo)
+
+	return postProcessExif(exifInfo)
+}
+
+// ExtractExif parses image file with a given path and extracts exif information. Values from XMP sidecar file are
+// merged if sidecar path is not empty, sidecar alone is used when the image cannot be read
+func ExtractExif(imageFilePath string, sidecarPath string, mmap bool) (*ExifInfo, error) {
+	exifInfos, err := ExtractImagesExif(imageFilePath, sidecarPath, mmap, false)
+	if err != nil {
+		return nil, err
+	}
+	return exifInfos[0], nil
+}
+
+// ExtractImagesExif works as ExtractExif, but also extracts exif information of the secondary images of
+// Multi-Picture Format file from the same parse if mpImages is set. Exif of the primary image is always the first one
+func ExtractImagesExif(imageFilePath string, sidecarPath string, mmap bool, mpImages bool) (exifInfos []*ExifInfo, err error) {
+	defer func() {
+		state := recover()
+		if state != nil {
+			logger.Verbose(2, fmt.Sprintf("Faulted while reading %s: %v", imageFilePath, state))
+			exifInfos = nil
+			err = fmt.Errorf("Faulted while reading %s: %v", imageFilePath, state)
+		}
+	}()
+	var sidecar exif.XmpProperties
+	if len(sidecarPath) > 0 {
+		sidecar, err = readSidecar(sidecarPath)
+		if err != nil {
+			logger.Verbose(1, fmt.Sprintf("\nFailed to read XMP sidecar '%s': %s", sidecarPath, err))
+		}
+	}
+	f, err := openImageFile(imageFilePath, mmap)
+	var metadata *exif.Metadata
+	if err == nil {
+		defer func() { f.Close() }()
+		metadata, err = exif.ReadMetadata(f)
+	}
+	if err != nil {
+		if len(sidecar) == 0 {
+			return nil, err
+		}
+		logger.Verbose(1, fmt.Sprintf("\nFailed to extract EXIF from '%s', using XMP sidecar: %s", imageFilePath, err))
+		metadata = &exif.Metadata{}
+	}
+	exifInfo := &ExifInfo{
+		FileName:  imageFilePath,
+		MediaType: mediaTypeImage,
+	}
+	exifInfos = append(exifInfos, metadataToExifInfo(metadata, sidecar, exifInfo))
+	if mpImages {
+		exifInfos = append(exifInfos, extractMpImagesExif(f, imageFilePath, metadata.Images)...)
+	}
+	return exifInfos, nil
+}
 
-	exifInfo = postProcessExif(exifInfo)
-	return exifInfo, nil
+// extracts exif information of the secondary images, i.e. the second image of a stereo pair. Large previews of the
+// primary image are skipped
+func extractMpImagesExif(f exif.File, imageFilePath string, images []exif.MpImage) (exifInfos []*ExifInfo) {
+	for index, image := range images {
+		if index == 0 || image.IsPreview() {
+			continue
+		}
+		imageMetadata, err := exif.ReadMpImageMetadata(f, image)
+		if err != nil {
+			logger.Verbose(1, fmt.Sprintf("\nFailed to read MP image %d of '%s': %s", index, imageFilePath, err))
+			continue
+		}
+		exifInfo := &ExifInfo{
+			FileName:   imageFilePath,
+			MediaType:  mediaTypeImage,
+			ImageIndex: index,
+		}
+		exifInfos = append(exifInfos, metadataToExifInfo(imageMetadata, nil, exifInfo))
+	}
+	return exifInfos
 }
@@ -40,10 +40,46 @@ func TestCameras(t *testing.T) {
 	options.ExtendFlash = true
 	for _, camera := range cameras {
 		filepath := "test-data/cameras/" + camera.Image
-		exifInfo, err := ExtractExif(filepath, false)
+		exifInfo, err := ExtractExif(filepath, "", false)
 		if err != nil {
 			t.Errorf("Failed to read exif from %s: %v", filepath, err)
+			continue
 		}
 		compareExifMaps(t, camera.Camera, camera.Exif, exifInfo.toMap())
 	}
 }
+
+func TestRatingOfAnyIntegerType(t *testing.T) {
+	tests := []struct {
+		entry  tiffEntry
+		rating int
+	}{
+		{shortEntry(0x4746, 3), 3},
+		{longEntry(0x4746, 4), 4},
+		// darktable writes rating as signed long
+		{signedLongEntry(0x4746, -1), -1},
+		{signedShortEntry(0x4746, 2), 2},
+		{asciiEntry(0x4746, "5"), 0},
+	}
+	for _, test := range tests {
+		exifInfo := readTestExif(t, buildIfd0Jpeg(asciiEntry(0x010f, "Canon"), test.entry))
+		if exifInfo.Rating != test.rating {
+			t.Errorf("Rating %d != %d for tag of type %d", exifInfo.Rating, test.rating, test.entry.Type)
+		}
+	}
+}
+
+func TestPrimaryImageComesFirstWithMpImages(t *testing.T) {
+	filepath := "test-data/cameras/Panasonic/PanasonicGX1.jpeg"
+	exifInfos, err := ExtractImagesExif(filepath, "", false, true)
+	if err != nil {
+		t.Fatalf("Failed to read exif from %s: %v", filepath, err)
+	}
+	// GX1 MPF index lists only the large preview of the primary image
+	if len(exifInfos) != 1 {
+		t.Fatalf("Expected only primary image, got %d images", len(exifInfos))
+	}
+	if exifInfos[0].ImageIndex != 0 || exifInfos[0].Model != "DMC-GX1" {
+		t.Errorf("Unexpected primary image %d of '%s'", exifInfos[0].ImageIndex, exifInfos[0].Model)
+	}
+}
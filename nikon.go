@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+const (
+	tagNikonQuality      = "8769/927c/0004"
+	tagNikonSerialNumber = "8769/927c/001d"
+	tagNikonLensType     = "8769/927c/0083"
+	tagNikonLens         = "8769/927c/0084"
+	tagNikonShutterCount = "8769/927c/00a7"
+	tagNikonLensData     = "8769/927c/0098"
+)
+
+// Nikon lenses identified by composite lens ID: lens ID number, lens f-stops, min and max focal length, max aperture
+// at min and max focal length, MCU version from lens data and lens type. Lenses which are not listed here are described
+// by their focal length and aperture range from the lens tag
+var nikonLensIDs = map[string]string{
+	"7A 3C 1F 37 30 30 7E 06": "AF-S DX Zoom-Nikkor 12-24mm f/4G IF-ED",
+	"7F 40 2D 5C 2C 34 84 06": "AF-S DX Zoom-Nikkor 18-70mm f/3.5-4.5G IF-ED",
+	"8C 40 2D 53 2C 3C 8E 06": "AF-S DX Zoom-Nikkor 18-55mm f/3.5-5.6G ED",
+	"A0 40 2D 53 2C 3C CA 0E": "AF-S DX VR Zoom-Nikkor 18-55mm f/3.5-5.6G",
+	"8B 40 2D 80 2C 3C FD 0E": "AF-S DX VR Zoom-Nikkor 18-200mm f/3.5-5.6G IF-ED",
+	"77 48 5C 80 24 24 7B 0E": "AF-S VR Zoom-Nikkor 70-200mm f/2.8G IF-ED",
+	"8D 44 5C 8E 34 3C 8F 0E": "AF-S VR Zoom-Nikkor 70-300mm f/4.5-5.6G IF-ED",
+	"8F 40 2D 72 2C 3C 91 06": "AF-S DX Zoom-Nikkor 18-135mm f/3.5-5.6G IF-ED",
+	"90 3B 53 80 30 3C 92 0E": "AF-S DX VR Zoom-Nikkor 55-200mm f/4-5.6G IF-ED",
+	"9E 40 2D 6A 2C 3C A0 0E": "AF-S DX VR Zoom-Nikkor 18-105mm f/3.5-5.6G ED",
+	"A1 40 18 37 2C 34 A3 06": "AF-S DX Nikkor 10-24mm f/3.5-4.5G ED",
+	"A2 48 5C 80 24 24 A4 0E": "AF-S Nikkor 70-200mm f/2.8G ED VR II",
+	"A5 40 3C 8E 2C 3C A7 0E": "AF-S Nikkor 28-300mm f/3.5-5.6G ED VR",
+	"AA 3C 37 6E 30 30 AC 0E": "AF-S Nikkor 24-120mm f/4G ED VR",
+	"AC 38 53 8E 34 3C AE 0E": "AF-S DX VR Nikkor 55-300mm f/4.5-5.6G ED",
+	"8A 54 6A 6A 24 24 8C 0E": "AF-S VR Micro-Nikkor 105mm f/2.8G IF-ED",
+	"A0 54 50 50 0C 0C A2 06": "AF-S Nikkor 50mm f/1.4G",
+	"AF 54 44 44 0C 0C B1 06": "AF-S Nikkor 35mm f/1.4G",
+	"AE 54 62 62 0C 0C B0 06": "AF-S Nikkor 85mm f/1.4G",
+	"76 58 50 50 14 14 05 02": "AF Nikkor 50mm f/1.8D",
+}
+
+// Nikon lens type flags
+var nikonLensTypeFlags = []struct {
+	Mask byte
+	Name string
+}{
+	{0x01, "MF"},
+	{0x02, "D"},
+	{0x04, "G"},
+	{0x40, "E"},
+	{0x08, "VR"},
+	{0x80, "AF-P"},
+}
+
+// extractNikon decodes shutter count, serial number, quality setting and lens from Nikon maker notes
+func extractNikon(tagMap map[string]exif.Tag, exifInfo *ExifInfo) {
+	if !strings.HasPrefix(strings.ToUpper(exifInfo.Make), "NIKON") {
+		return
+	}
+	if shutterCount, ok := tagNumber(tagMap, tagNikonShutterCount); ok {
+		exifInfo.ShutterCount = shutterCount
+	}
+	if len(exifInfo.SerialNumber) == 0 {
+		exifInfo.SerialNumber = strings.TrimSpace(tagString(tagMap, tagNikonSerialNumber))
+	}
+	exifInfo.QualitySetting = qualitySetting(tagMap, tagNikonQuality)
+	if len(exifInfo.LensModel) == 0 {
+		exifInfo.LensModel = nikonLensName(tagMap)
+	}
+}
+
+// nikonLensName resolves composite lens ID to the lens name. Unknown lenses are described by their focal length and
+// aperture range and lens type, i.e. "18-105mm f/3.5-5.6 G VR"
+func nikonLensName(tagMap map[string]exif.Tag) string {
+	lensType, hasLensType := tagNumber(tagMap, tagNikonLensType)
+	lensID := make([]string, 0, 8)
+	for index := 0; index < 7; index++ {
+		value, ok := tagNumber(tagMap, fmt.Sprintf("%s/%04x", tagNikonLensData, index))
+		if !ok {
+			break
+		}
+		lensID = append(lensID, fmt.Sprintf("%02X", value))
+	}
+	if len(lensID) == 7 && hasLensType {
+		lensID = append(lensID, fmt.Sprintf("%02X", lensType))
+		if lens, ok := nikonLensIDs[strings.Join(lensID, " ")]; ok {
+			return lens
+		}
+	}
+	lens := tagRationals(tagMap, tagNikonLens)
+	if len(lens) < 4 || lens[0].Denominator == 0 || lens[1].Denominator == 0 || lens[2].Denominator == 0 || lens[3].Denominator == 0 {
+		return ""
+	}
+	name := fmt.Sprintf("%gmm f/%g", lens[0].AsFloat(), lens[2].AsFloat())
+	if lens[0].AsFloat() != lens[1].AsFloat() {
+		name = fmt.Sprintf("%g-%gmm f/%g", lens[0].AsFloat(), lens[1].AsFloat(), lens[2].AsFloat())
+		if lens[2].AsFloat() != lens[3].AsFloat() {
+			name += fmt.Sprintf("-%g", lens[3].AsFloat())
+		}
+	}
+	for _, flag := range nikonLensTypeFlags {
+		if hasLensType && byte(lensType)&flag.Mask != 0 {
+			name += " " + flag.Name
+		}
+	}
+	return name
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+// nikonMakerNote creates Nikon type 3 maker notes: header followed by big endian TIFF structure, offsets are relative
+// to the TIFF header of maker notes
+func nikonMakerNote(entries ...tiffEntry) []byte {
+	header := []byte("Nikon\x00\x02\x10\x00\x00MM\x00\x2a\x00\x00\x00\x08")
+	return append(header, buildIfd(binary.BigEndian, 8, entries...)...)
+}
+
+// nikonLensData creates unencrypted lens data of version 0100 with the given composite lens ID values
+func nikonLensData(lensID ...byte) tiffEntry {
+	data := append([]byte("0100\x00\x00"), lensID...)
+	return undefinedEntry(exif.NikonLensDataTagID, append(data, make([]byte, 8)...))
+}
+
+func TestExtractNikon(t *testing.T) {
+	tests := []struct {
+		name         string
+		entries      []tiffEntry
+		lens         string
+		serial       string
+		quality      string
+		shutterCount uint32
+	}{
+		{"known lens", []tiffEntry{
+			asciiEntry(exif.NikonQualityTagID, "FINE  "),
+			asciiEntry(exif.NikonSerialNumberTagID, "3012345"),
+			byteEntry(exif.NikonLensTypeTagID, 0x06),
+			nikonLensData(0x7F, 0x40, 0x2D, 0x5C, 0x2C, 0x34, 0x84),
+			longEntry(exif.NikonShutterCountTagID, 12345),
+		}, "AF-S DX Zoom-Nikkor 18-70mm f/3.5-4.5G IF-ED", "3012345", "Fine", 12345},
+		{"unknown lens", []tiffEntry{
+			asciiEntry(exif.NikonQualityTagID, "NORMAL"),
+			byteEntry(exif.NikonLensTypeTagID, 0x0E),
+			rationalEntry(exif.NikonLensTagID, 180, 10, 1050, 10, 35, 10, 56, 10),
+			nikonLensData(0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07),
+		}, "18-105mm f/3.5-5.6 D G VR", "", "Normal", 0},
+		{"prime lens without lens data", []tiffEntry{
+			asciiEntry(exif.NikonQualityTagID, "RAW"),
+			byteEntry(exif.NikonLensTypeTagID, 0x02),
+			rationalEntry(exif.NikonLensTagID, 50, 1, 50, 1, 14, 10, 14, 10),
+		}, "50mm f/1.4 D", "", "RAW", 0},
+		{"known D lens", []tiffEntry{
+			byteEntry(exif.NikonLensTypeTagID, 0x02),
+			nikonLensData(0x76, 0x58, 0x50, 0x50, 0x14, 0x14, 0x05),
+		}, "AF Nikkor 50mm f/1.8D", "", "", 0},
+	}
+	for _, test := range tests {
+		entries := test.entries
+		exifInfo := readTestExif(t, buildMakerNoteJpeg("NIKON CORPORATION", "NIKON D7000", func(offset uint32) []byte {
+			return nikonMakerNote(entries...)
+		}))
+		if exifInfo.LensModel != test.lens {
+			t.Errorf("%s: lens model '%s' != '%s'", test.name, exifInfo.LensModel, test.lens)
+		}
+		if exifInfo.SerialNumber != test.serial {
+			t.Errorf("%s: serial number '%s' != '%s'", test.name, exifInfo.SerialNumber, test.serial)
+		}
+		if exifInfo.QualitySetting != test.quality {
+			t.Errorf("%s: quality setting '%s' != '%s'", test.name, exifInfo.QualitySetting, test.quality)
+		}
+		if exifInfo.ShutterCount != test.shutterCount {
+			t.Errorf("%s: shutter count %d != %d", test.name, exifInfo.ShutterCount, test.shutterCount)
+		}
+	}
+}
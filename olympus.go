@@ -0,0 +1,39 @@
+package main
+
+import (
+	"math"
+	"strings"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+const (
+	tagOlympusBodySerialNumber = "8769/927c/2010/0101"
+	tagOlympusLensSerialNumber = "8769/927c/2010/0202"
+	tagOlympusLensModel        = "8769/927c/2010/0203"
+	tagOlympusFocusDistance    = "8769/927c/2050/0305"
+)
+
+// extractOlympus decodes lens model, lens and body serial numbers and focus distance from Olympus maker notes.
+// OM System cameras use the same maker notes
+func extractOlympus(tagMap map[string]exif.Tag, exifInfo *ExifInfo) {
+	cameraMake := strings.ToUpper(exifInfo.Make)
+	if !strings.HasPrefix(cameraMake, "OLYMPUS") && !strings.HasPrefix(cameraMake, "OM DIGITAL") {
+		return
+	}
+	if len(exifInfo.LensModel) == 0 {
+		exifInfo.LensModel = strings.TrimSpace(tagString(tagMap, tagOlympusLensModel))
+	}
+	if len(exifInfo.SerialNumber) == 0 {
+		exifInfo.SerialNumber = strings.TrimSpace(tagString(tagMap, tagOlympusBodySerialNumber))
+	}
+	exifInfo.LensSerialNumber = strings.TrimSpace(tagString(tagMap, tagOlympusLensSerialNumber))
+	// numerator of focus distance is in millimetres whatever the denominator is, 0xFFFFFFFF means infinity
+	if distance := tagRationals(tagMap, tagOlympusFocusDistance); len(distance) > 0 {
+		if distance[0].Numerator == math.MaxUint32 {
+			exifInfo.FocusDistance = math.Inf(1)
+		} else {
+			exifInfo.FocusDistance = float64(distance[0].Numerator) / 1000
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+// olympusMakerNote creates big endian Olympus maker notes with equipment and focus info sub-IFDs. Sub-IFDs are the
+// values of the main IFD, their offsets are relative to the start of maker notes
+func olympusMakerNote(equipment []tiffEntry, focusInfo []tiffEntry) []byte {
+	header := []byte("OLYMPUS\x00MM\x03\x00")
+	valuesStart := uint32(len(header)) + 2 + 2*12 + 4
+	equipmentIfd := buildIfd(binary.BigEndian, valuesStart, equipment...)
+	if len(equipmentIfd)%2 != 0 {
+		equipmentIfd = append(equipmentIfd, 0)
+	}
+	focusInfoIfd := buildIfd(binary.BigEndian, valuesStart+uint32(len(equipmentIfd)), focusInfo...)
+	return buildMakerNote(binary.BigEndian, 0, header,
+		undefinedEntry(exif.OlympusEquipmentTagID, equipmentIfd),
+		undefinedEntry(exif.OlympusFocusInfoTagID, focusInfoIfd))
+}
+
+func TestExtractOlympus(t *testing.T) {
+	tests := []struct {
+		name          string
+		cameraMake    string
+		focusDistance []uint32
+		lens          string
+		serial        string
+		lensSerial    string
+		distance      float64
+	}{
+		// numerator is in millimetres, denominator differs between camera models
+		{"E-1 distance in mm over 1", "OLYMPUS IMAGING CORP.", []uint32{1250, 1}, "OLYMPUS M.12-40mm F2.8",
+			"BHP123456", "ABF987654", 1.25},
+		{"E-300 distance in mm over 10", "OLYMPUS IMAGING CORP.", []uint32{1250, 10}, "OLYMPUS M.12-40mm F2.8",
+			"BHP123456", "ABF987654", 1.25},
+		{"distance in mm over 1000", "OLYMPUS CORPORATION", []uint32{830, 1000}, "OLYMPUS M.12-40mm F2.8",
+			"BHP123456", "ABF987654", 0.83},
+		{"OM System", "OM Digital Solutions", []uint32{3000, 1000}, "OLYMPUS M.12-40mm F2.8", "BHP123456",
+			"ABF987654", 3},
+		{"infinity", "OLYMPUS CORPORATION", []uint32{math.MaxUint32, 1000}, "OLYMPUS M.12-40mm F2.8", "BHP123456",
+			"ABF987654", math.Inf(1)},
+		{"other make", "Panasonic", []uint32{1250, 1}, "", "", "", 0},
+	}
+	for _, test := range tests {
+		focusDistance := test.focusDistance
+		exifInfo := readTestExif(t, buildMakerNoteJpeg(test.cameraMake, "E-M1", func(offset uint32) []byte {
+			return olympusMakerNote([]tiffEntry{
+				asciiEntry(exif.OlympusBodySerialNumberTagID, "BHP123456"),
+				asciiEntry(exif.OlympusLensSerialNumberTagID, "ABF987654 "),
+				asciiEntry(exif.OlympusLensModelTagID, "OLYMPUS M.12-40mm F2.8"),
+			}, []tiffEntry{
+				rationalEntry(exif.OlympusFocusDistanceTagID, focusDistance...),
+			})
+		}))
+		if exifInfo.LensModel != test.lens {
+			t.Errorf("%s: lens model '%s' != '%s'", test.name, exifInfo.LensModel, test.lens)
+		}
+		if exifInfo.SerialNumber != test.serial {
+			t.Errorf("%s: serial number '%s' != '%s'", test.name, exifInfo.SerialNumber, test.serial)
+		}
+		if exifInfo.LensSerialNumber != test.lensSerial {
+			t.Errorf("%s: lens serial number '%s' != '%s'", test.name, exifInfo.LensSerialNumber, test.lensSerial)
+		}
+		if exifInfo.FocusDistance != test.distance {
+			t.Errorf("%s: focus distance %f != %f", test.name, exifInfo.FocusDistance, test.distance)
+		}
+	}
+}
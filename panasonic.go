@@ -0,0 +1,125 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+const (
+	tagPanasonicImageQuality         = "8769/927c/0001"
+	tagPanasonicShootingMode         = "8769/927c/001f"
+	tagPanasonicInternalSerialNumber = "8769/927c/0025"
+	tagPanasonicBurstMode            = "8769/927c/002a"
+	tagPanasonicProgramIso           = "8769/927c/003c"
+	tagPanasonicLensType             = "8769/927c/0051"
+	tagPanasonicLensSerialNumber     = "8769/927c/0052"
+	tagPanasonicSceneMode            = "8769/927c/8001"
+)
+
+// shooting mode contains the scene detected by intelligent auto mode for the newer cameras
+var panasonicShootingModes = map[uint16]string{
+	1:  "Normal",
+	2:  "Portrait",
+	3:  "Scenery",
+	4:  "Sports",
+	5:  "Night Portrait",
+	6:  "Program",
+	7:  "Aperture Priority",
+	8:  "Shutter Priority",
+	9:  "Macro",
+	10: "Spot",
+	11: "Manual",
+	12: "Movie Preview",
+	13: "Panning",
+	14: "Simple",
+	15: "Color Effects",
+	16: "Self Portrait",
+	17: "Economy",
+	18: "Fireworks",
+	19: "Party",
+	20: "Snow",
+	21: "Night Scenery",
+	22: "Food",
+	23: "Baby",
+	24: "Soft Skin",
+	25: "Candlelight",
+	26: "Starry Night",
+	27: "High Sensitivity",
+	28: "Panorama Assist",
+	29: "Underwater",
+	30: "Beach",
+	31: "Aerial Photo",
+	32: "Sunset",
+	33: "Pet",
+	34: "Intelligent ISO",
+	35: "Clipboard",
+	36: "High Speed Continuous Shooting",
+	37: "Intelligent Auto",
+}
+
+var panasonicBurstModes = map[uint16]string{
+	0:  "Off",
+	1:  "On",
+	2:  "Auto Exposure Bracketing",
+	3:  "Focus Bracketing",
+	4:  "Unlimited",
+	8:  "White Balance Bracketing",
+	17: "On (with flash)",
+	18: "Aperture Bracketing",
+}
+
+// JPEG quality settings, "High" is shown as "Fine" and "Normal" as "Standard" by the cameras
+var panasonicImageQualities = map[uint32]string{
+	1: "TIFF",
+	2: "High",
+	3: "Normal",
+	6: "Very High",
+	7: "RAW",
+}
+
+// program ISO values which are not real ISO
+const (
+	panasonicIntelligentIso = 65534
+	panasonicIsoNotSet      = 65535
+)
+
+// extractPanasonic decodes lens, serial numbers, scene and burst modes, quality setting and ISO from Panasonic maker
+// notes
+func extractPanasonic(tagMap map[string]exif.Tag, exifInfo *ExifInfo) {
+	cameraMake := strings.ToUpper(exifInfo.Make)
+	if !strings.HasPrefix(cameraMake, "PANASONIC") && !strings.HasPrefix(cameraMake, "LEICA") {
+		return
+	}
+	if len(exifInfo.LensModel) == 0 {
+		exifInfo.LensModel = strings.TrimSpace(tagString(tagMap, tagPanasonicLensType))
+	}
+	if len(exifInfo.LensSerialNumber) == 0 {
+		exifInfo.LensSerialNumber = strings.TrimSpace(tagString(tagMap, tagPanasonicLensSerialNumber))
+	}
+	if len(exifInfo.SerialNumber) == 0 {
+		if tag, ok := tagMap[tagPanasonicInternalSerialNumber]; ok {
+			if serial, ok := tag.Value.([]byte); ok {
+				exifInfo.SerialNumber = strings.TrimSpace(strings.TrimRight(string(serial), "\x00"))
+			}
+		}
+	}
+	// scene mode is set only for scene modes, otherwise the shooting mode is used
+	if sceneMode, ok := panasonicShootingModes[tagShort(tagMap, tagPanasonicSceneMode)]; ok {
+		exifInfo.SceneMode = sceneMode
+	} else if shootingMode, ok := panasonicShootingModes[tagShort(tagMap, tagPanasonicShootingMode)]; ok {
+		exifInfo.SceneMode = shootingMode
+	}
+	if _, ok := tagMap[tagPanasonicBurstMode]; ok {
+		exifInfo.DriveMode = panasonicBurstModes[tagShort(tagMap, tagPanasonicBurstMode)]
+	}
+	if quality, ok := tagNumber(tagMap, tagPanasonicImageQuality); ok {
+		exifInfo.QualitySetting = panasonicImageQualities[quality]
+	}
+	// Exif ISO is capped at 65535
+	if exifInfo.Iso == 0 || exifInfo.Iso == 65535 {
+		if iso := tagShort(tagMap, tagPanasonicProgramIso); iso != 0 && iso != panasonicIntelligentIso && iso != panasonicIsoNotSet {
+			exifInfo.Iso = iso
+		}
+	}
+}
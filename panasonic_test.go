@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+func TestExtractPanasonic(t *testing.T) {
+	tests := []struct {
+		name      string
+		order     binary.ByteOrder
+		entries   []tiffEntry
+		lens      string
+		serial    string
+		sceneMode string
+		driveMode string
+		quality   string
+		iso       uint16
+	}{
+		{"lens and serial numbers", binary.BigEndian, []tiffEntry{
+			asciiEntry(exif.PanasonicLensTypeTagID, "LUMIX G VARIO 12-32/F3.5-5.6 "),
+			asciiEntry(exif.PanasonicLensSerialNumberTagID, "XA1234567"),
+			undefinedEntry(exif.PanasonicInternalSerialNumberTagID, []byte("F541606150234\x00\x00\x00")),
+		}, "LUMIX G VARIO 12-32/F3.5-5.6", "F541606150234", "", "", "", 0},
+		{"scene mode overrides shooting mode", binary.BigEndian, []tiffEntry{
+			shortEntry(exif.PanasonicShootingModeTagID, 37),
+			shortEntry(exif.PanasonicSceneModeTagID, 22),
+		}, "", "", "Food", "", "", 0},
+		{"intelligent auto", binary.BigEndian, []tiffEntry{
+			shortEntry(exif.PanasonicShootingModeTagID, 37),
+			shortEntry(exif.PanasonicSceneModeTagID, 0),
+		}, "", "", "Intelligent Auto", "", "", 0},
+		{"little endian maker notes", binary.LittleEndian, []tiffEntry{
+			shortEntry(exif.PanasonicImageQualityTagID, 2),
+			shortEntry(exif.PanasonicBurstModeTagID, 2),
+			shortEntry(exif.PanasonicProgramIsoTagID, 25600),
+		}, "", "", "", "Auto Exposure Bracketing", "High", 25600},
+		{"intelligent ISO", binary.BigEndian, []tiffEntry{
+			shortEntry(exif.PanasonicProgramIsoTagID, 65534),
+		}, "", "", "", "", "", 0},
+	}
+	for _, test := range tests {
+		order, entries := test.order, test.entries
+		exifInfo := readTestExif(t, buildMakerNoteJpeg("Panasonic", "DMC-GX85", func(offset uint32) []byte {
+			return buildMakerNote(order, offset, []byte("Panasonic\x00\x00\x00"), entries...)
+		}))
+		if exifInfo.LensModel != test.lens {
+			t.Errorf("%s: lens model '%s' != '%s'", test.name, exifInfo.LensModel, test.lens)
+		}
+		if exifInfo.SerialNumber != test.serial {
+			t.Errorf("%s: serial number '%s' != '%s'", test.name, exifInfo.SerialNumber, test.serial)
+		}
+		if exifInfo.SceneMode != test.sceneMode {
+			t.Errorf("%s: scene mode '%s' != '%s'", test.name, exifInfo.SceneMode, test.sceneMode)
+		}
+		if exifInfo.DriveMode != test.driveMode {
+			t.Errorf("%s: drive mode '%s' != '%s'", test.name, exifInfo.DriveMode, test.driveMode)
+		}
+		if exifInfo.QualitySetting != test.quality {
+			t.Errorf("%s: quality setting '%s' != '%s'", test.name, exifInfo.QualitySetting, test.quality)
+		}
+		if exifInfo.Iso != test.iso {
+			t.Errorf("%s: ISO %d != %d", test.name, exifInfo.Iso, test.iso)
+		}
+	}
+}
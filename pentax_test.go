@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+// pentaxShutterCount creates encrypted shutter count, date and time entries of Pentax maker notes
+func pentaxShutterCount(count uint32, date []byte, time []byte) []tiffEntry {
+	dateKey := binary.BigEndian.Uint32(date)
+	timeKey := uint32(time[0])<<24 | uint32(time[1])<<16 | uint32(time[2])<<8
+	encrypted := make([]byte, 4)
+	binary.BigEndian.PutUint32(encrypted, count^dateKey^^timeKey)
+	return []tiffEntry{
+		undefinedEntry(exif.PentaxDateTagID, date),
+		undefinedEntry(exif.PentaxTimeTagID, time),
+		undefinedEntry(exif.PentaxShutterCountTagID, encrypted),
+	}
+}
+
+func TestExtractPentax(t *testing.T) {
+	tests := []struct {
+		name          string
+		makerNote     func(offset uint32, entries []tiffEntry) []byte
+		entries       []tiffEntry
+		lens          string
+		serial        string
+		stabilization string
+		quality       string
+		shutterCount  uint32
+	}{
+		{"AOC maker notes", pentaxAocMakerNote, []tiffEntry{
+			shortEntry(exif.PentaxQualityTagID, 2),
+			byteEntry(exif.PentaxLensRecTagID, 7, 251, 0, 0),
+			undefinedEntry(exif.PentaxShakeReductionInfoTagID, []byte{0, 1, 0, 0}),
+			asciiEntry(exif.PentaxSerialNumberTagID, "4123456"),
+		}, "smc PENTAX-DA 40mm F2.8 Limited", "4123456", "On", "Best", 0},
+		{"PENTAX maker notes", pentaxMakerNote, append(pentaxShutterCount(12345, []byte{0x07, 0xE5, 0x05, 0x01},
+			[]byte{12, 34, 56}),
+			undefinedEntry(exif.PentaxShakeReductionInfoTagID, []byte{0, 9, 0, 0}),
+		), "", "", "On", "", 12345},
+		{"unknown lens", pentaxAocMakerNote, []tiffEntry{
+			byteEntry(exif.PentaxLensRecTagID, 7, 1, 0, 0),
+			undefinedEntry(exif.PentaxShakeReductionInfoTagID, []byte{0, 5, 0, 0}),
+		}, "", "", "On but Disabled", "", 0},
+		{"HD D FA lens", pentaxAocMakerNote, []tiffEntry{
+			byteEntry(exif.PentaxLensRecTagID, 8, 62, 0, 0),
+		}, "HD PENTAX-D FA 24-70mm F2.8 ED SDM WR", "", "", "", 0},
+	}
+	for _, test := range tests {
+		makerNote, entries := test.makerNote, test.entries
+		exifInfo := readTestExif(t, buildMakerNoteJpeg("PENTAX", "PENTAX K-3", func(offset uint32) []byte {
+			return makerNote(offset, entries)
+		}))
+		if exifInfo.LensModel != test.lens {
+			t.Errorf("%s: lens model '%s' != '%s'", test.name, exifInfo.LensModel, test.lens)
+		}
+		if exifInfo.SerialNumber != test.serial {
+			t.Errorf("%s: serial number '%s' != '%s'", test.name, exifInfo.SerialNumber, test.serial)
+		}
+		if exifInfo.Stabilization != test.stabilization {
+			t.Errorf("%s: stabilization '%s' != '%s'", test.name, exifInfo.Stabilization, test.stabilization)
+		}
+		if exifInfo.QualitySetting != test.quality {
+			t.Errorf("%s: quality setting '%s' != '%s'", test.name, exifInfo.QualitySetting, test.quality)
+		}
+		if exifInfo.ShutterCount != test.shutterCount {
+			t.Errorf("%s: shutter count %d != %d", test.name, exifInfo.ShutterCount, test.shutterCount)
+		}
+	}
+}
+
+// AOC maker notes have offsets relative to the main TIFF header
+func pentaxAocMakerNote(offset uint32, entries []tiffEntry) []byte {
+	return buildMakerNote(binary.BigEndian, offset, []byte("AOC\x00MM"), entries...)
+}
+
+// PENTAX maker notes have offsets relative to the start of maker notes
+func pentaxMakerNote(offset uint32, entries []tiffEntry) []byte {
+	return buildMakerNote(binary.LittleEndian, 0, []byte("PENTAX \x00II"), entries...)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"math"
+	"strings"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+const (
+	tagSonyShutterCount = "8769/927c/9050/0032"
+	tagSonyLensMount    = "8769/927c/9050/0105"
+	tagSonyLensTypeE    = "8769/927c/9050/0107"
+	tagSonyLensTypeA    = "8769/927c/9050/0109"
+	tagSonyIso          = "8769/927c/2010/1218"
+)
+
+// Sony E-mount lens types
+var sonyELensTypes = map[uint16]string{
+	32784: "Sony E 16mm F2.8",
+	32785: "Sony E 18-55mm F3.5-5.6 OSS",
+	32786: "Sony E 55-210mm F4.5-6.3 OSS",
+	32787: "Sony E 18-200mm F3.5-6.3 OSS",
+	32788: "Sony E 30mm F3.5 Macro",
+	32789: "Sony E 24mm F1.8 ZA",
+	32790: "Sony E 50mm F1.8 OSS",
+	32791: "Sony E 16-70mm F4 ZA OSS",
+	32792: "Sony E 10-18mm F4 OSS",
+	32793: "Sony E PZ 16-50mm F3.5-5.6 OSS",
+}
+
+// Minolta and Sony A-mount lens types. Types which ExifTool shares between several lenses are not listed
+var sonyALensTypes = map[uint16]string{
+	0:    "Minolta AF 28-85mm F3.5-4.5 New",
+	1:    "Minolta AF 80-200mm F2.8 HS-APO G",
+	2:    "Minolta AF 28-70mm F2.8 G",
+	3:    "Minolta AF 28-80mm F4-5.6",
+	4:    "Minolta AF 85mm F1.4G",
+	5:    "Minolta AF 35-70mm F3.5-4.5 [II]",
+	6:    "Minolta AF 24-85mm F3.5-4.5 [New]",
+	8:    "Minolta AF 70-210mm F4.5-5.6 [II]",
+	9:    "Minolta AF 50mm F3.5 Macro",
+	10:   "Minolta AF 28-105mm F3.5-4.5 [New]",
+	11:   "Minolta AF 300mm F4 HS-APO G",
+	12:   "Minolta AF 100mm F2.8 Soft Focus",
+	13:   "Minolta AF 75-300mm F4.5-5.6 (New or II)",
+	14:   "Minolta AF 100-400mm F4.5-6.7 APO",
+	15:   "Minolta AF 400mm F4.5 HS-APO G",
+	16:   "Minolta AF 17-35mm F3.5 G",
+	17:   "Minolta AF 20-35mm F3.5-4.5",
+	18:   "Minolta AF 28-80mm F3.5-5.6 II",
+	19:   "Minolta AF 35mm F1.4 G",
+	20:   "Minolta/Sony 135mm F2.8 [T4.5] STF",
+	22:   "Minolta AF 35-80mm F4-5.6 II",
+	23:   "Minolta AF 200mm F4 Macro APO G",
+	29:   "Minolta/Sony AF 75-300mm F4.5-5.6 (D)",
+	33:   "Minolta/Sony AF 70-200mm F2.8 G",
+	38:   "Minolta AF 17-35mm F2.8-4 (D)",
+	39:   "Minolta AF 28-75mm F2.8 (D)",
+	40:   "Minolta/Sony AF DT 18-70mm F3.5-5.6 (D)",
+	42:   "Minolta/Sony AF DT 18-200mm F3.5-6.3 (D)",
+	43:   "Sony 35mm F1.4 G (SAL35F14G)",
+	44:   "Sony 50mm F1.4 (SAL50F14)",
+	45:   "Carl Zeiss Planar T* 85mm F1.4 ZA (SAL85F14Z)",
+	46:   "Carl Zeiss Vario-Sonnar T* DT 16-80mm F3.5-4.5 ZA (SAL1680Z)",
+	47:   "Carl Zeiss Sonnar T* 135mm F1.8 ZA (SAL135F18Z)",
+	49:   "Sony DT 55-200mm F4-5.6 (SAL55200)",
+	50:   "Sony DT 18-250mm F3.5-6.3 (SAL18250)",
+	51:   "Sony DT 16-105mm F3.5-5.6 (SAL16105)",
+	53:   "Sony 70-400mm F4-5.6 G SSM (SAL70400G)",
+	54:   "Carl Zeiss Vario-Sonnar T* 16-35mm F2.8 ZA SSM (SAL1635Z) or ZA SSM II",
+	55:   "Sony DT 18-55mm F3.5-5.6 SAM (SAL1855) or SAM II",
+	56:   "Sony DT 55-200mm F4-5.6 SAM (SAL55200-2)",
+	58:   "Sony DT 30mm F2.8 Macro SAM (SAL30M28)",
+	59:   "Sony 28-75mm F2.8 SAM (SAL2875)",
+	60:   "Carl Zeiss Distagon T* 24mm F2 ZA SSM (SAL24F20Z)",
+	61:   "Sony 85mm F2.8 SAM (SAL85F28)",
+	62:   "Sony DT 35mm F1.8 SAM (SAL35F18)",
+	63:   "Sony DT 16-50mm F2.8 SSM (SAL1650)",
+	64:   "Sony 500mm F4 G SSM (SAL500F40G)",
+	65:   "Sony DT 18-135mm F3.5-5.6 SAM (SAL18135)",
+	66:   "Sony 300mm F2.8 G SSM II (SAL300F28G2)",
+	67:   "Sony 70-200mm F2.8 G SSM II (SAL70200G2)",
+	68:   "Sony DT 55-300mm F4.5-5.6 SAM (SAL55300)",
+	69:   "Sony 70-400mm F4-5.6 G SSM II (SAL70400G2)",
+	70:   "Carl Zeiss Planar T* 50mm F1.4 ZA SSM (SAL50F14Z)",
+	2550: "Minolta AF 50mm F1.7",
+	2557: "Minolta/Sony AF 28mm F2.8",
+	2562: "Minolta AF 50mm F1.4 [New]",
+	2572: "Minolta/Sony AF 500mm F8 Reflex",
+}
+
+// extractSony decodes values from Sony enciphered maker notes blocks: shutter count, lens and ISO
+func extractSony(tagMap map[string]exif.Tag, exifInfo *ExifInfo) {
+	if !strings.HasPrefix(strings.ToUpper(exifInfo.Make), "SONY") {
+		return
+	}
+	if shutterCount, ok := tagNumber(tagMap, tagSonyShutterCount); ok && exifInfo.ShutterCount == 0 {
+		exifInfo.ShutterCount = shutterCount
+	}
+	if len(exifInfo.LensModel) == 0 {
+		extractSonyLens(tagMap, exifInfo)
+	}
+	if _, ok := tagMap[tagSonyIso]; ok && exifInfo.Iso == 0 {
+		iso := math.Round(100 * math.Pow(2, 16-float64(tagShort(tagMap, tagSonyIso))/256))
+		if iso > 0 && iso < math.MaxUint16 {
+			exifInfo.Iso = uint16(iso)
+		}
+	}
+}
+
+// E-mount lenses are identified by E-mount lens type. A-mount lenses are identified by A-mount lens type, it is used
+// for E-mount cameras too when E-mount lens type is unknown or is the type of LA-EA adapter. Lens model of unknown
+// lenses is left empty
+func extractSonyLens(tagMap map[string]exif.Tag, exifInfo *ExifInfo) {
+	tag, ok := tagMap[tagSonyLensMount]
+	if !ok {
+		return
+	}
+	mount, ok := tag.Value.([]byte)
+	if !ok || len(mount) == 0 {
+		return
+	}
+	if mount[0] == exif.SonyLensMountE {
+		if lens, ok := sonyELensTypes[tagShort(tagMap, tagSonyLensTypeE)]; ok {
+			exifInfo.LensModel = lens
+			return
+		}
+	}
+	if _, ok := tagMap[tagSonyLensTypeA]; ok {
+		exifInfo.LensModel = sonyALensTypes[tagShort(tagMap, tagSonyLensTypeA)]
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/binary"
+	"sort"
+	"testing"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+// sonyBlock creates enciphered maker notes block with the given little endian values at their offsets. Values are
+// written in the order of their offsets, so that zero high bytes of a value never overwrite the next value
+func sonyBlock(size int, values map[int]uint32) []byte {
+	block := make([]byte, size)
+	offsets := make([]int, 0, len(values))
+	for offset := range values {
+		offsets = append(offsets, offset)
+	}
+	sort.Ints(offsets)
+	for _, offset := range offsets {
+		binary.LittleEndian.PutUint32(block[offset:], values[offset])
+	}
+	for i, b := range block {
+		if b < 249 {
+			block[i] = byte(int(b) * int(b) * int(b) % 249)
+		}
+	}
+	return block
+}
+
+func TestExtractSony(t *testing.T) {
+	tests := []struct {
+		name         string
+		model        string
+		entries      []tiffEntry
+		lens         string
+		iso          uint16
+		shutterCount uint32
+	}{
+		{"E-mount lens and shutter count", "NEX-6", []tiffEntry{
+			undefinedEntry(exif.SonyTag9050ID, sonyBlock(0x200, map[int]uint32{
+				exif.SonyShutterCountOffset: 23456,
+				exif.SonyLensMountOffset:    exif.SonyLensMountE,
+				exif.SonyLensTypeEOffset:    32790,
+			})),
+		}, "Sony E 50mm F1.8 OSS", 0, 23456},
+		// A-mount lens type of E-mount lenses is 65535
+		{"unknown E-mount lens", "NEX-6", []tiffEntry{
+			undefinedEntry(exif.SonyTag9050ID, sonyBlock(0x200, map[int]uint32{
+				exif.SonyLensMountOffset: exif.SonyLensMountE,
+				exif.SonyLensTypeEOffset: 32700,
+				exif.SonyLensTypeAOffset: 65535,
+			})),
+		}, "", 0, 0},
+		{"A-mount lens", "SLT-A99", []tiffEntry{
+			undefinedEntry(exif.SonyTag9050ID, sonyBlock(0x200, map[int]uint32{
+				exif.SonyLensMountOffset: exif.SonyLensMountA,
+				exif.SonyLensTypeAOffset: 45,
+			})),
+		}, "Carl Zeiss Planar T* 85mm F1.4 ZA (SAL85F14Z)", 0, 0},
+		{"unknown A-mount lens", "SLT-A99", []tiffEntry{
+			undefinedEntry(exif.SonyTag9050ID, sonyBlock(0x200, map[int]uint32{
+				exif.SonyLensMountOffset: exif.SonyLensMountA,
+				exif.SonyLensTypeAOffset: 128,
+			})),
+		}, "", 0, 0},
+		// LA-EA2 adapter has E-mount lens type 2
+		{"A-mount lens on adapter", "NEX-6", []tiffEntry{
+			undefinedEntry(exif.SonyTag9050ID, sonyBlock(0x200, map[int]uint32{
+				exif.SonyLensMountOffset: exif.SonyLensMountE,
+				exif.SonyLensTypeEOffset: 2,
+				exif.SonyLensTypeAOffset: 62,
+			})),
+		}, "Sony DT 35mm F1.8 SAM (SAL35F18)", 0, 0},
+		// ISO is 100 * 2^(16 - value/256)
+		{"ISO 200", "NEX-6", []tiffEntry{
+			undefinedEntry(exif.SonyTag2010ID, sonyBlock(0x1400, map[int]uint32{exif.SonyIsoOffset: 3840})),
+		}, "", 200, 0},
+		{"ISO 6400", "SLT-A99", []tiffEntry{
+			undefinedEntry(exif.SonyTag2010ID, sonyBlock(0x1400, map[int]uint32{exif.SonyIsoOffset: 2560})),
+		}, "", 6400, 0},
+		{"unknown layout", "ILCE-7M3", []tiffEntry{
+			undefinedEntry(exif.SonyTag9050ID, sonyBlock(0x200, map[int]uint32{
+				exif.SonyShutterCountOffset: 23456,
+				exif.SonyLensMountOffset:    exif.SonyLensMountE,
+				exif.SonyLensTypeEOffset:    32790,
+			})),
+			undefinedEntry(exif.SonyTag2010ID, sonyBlock(0x1400, map[int]uint32{exif.SonyIsoOffset: 3840})),
+		}, "", 0, 0},
+	}
+	for _, test := range tests {
+		entries := test.entries
+		exifInfo := readTestExif(t, buildMakerNoteJpeg("SONY", test.model, func(offset uint32) []byte {
+			return buildMakerNote(binary.BigEndian, offset, []byte("SONY DSC \x00\x00\x00"), entries...)
+		}))
+		if exifInfo.LensModel != test.lens {
+			t.Errorf("%s: lens model '%s' != '%s'", test.name, exifInfo.LensModel, test.lens)
+		}
+		if exifInfo.Iso != test.iso {
+			t.Errorf("%s: ISO %d != %d", test.name, exifInfo.Iso, test.iso)
+		}
+		if exifInfo.ShutterCount != test.shutterCount {
+			t.Errorf("%s: shutter count %d != %d", test.name, exifInfo.ShutterCount, test.shutterCount)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/uaraven/exif-stat/exif"
+	"github.com/uaraven/exif-stat/logger"
+)
+
+const thumbnailsCommand = "thumbnails"
+
+var (
+	thumbnailOptions = &struct {
+		Args struct {
+			FolderPath string
+		} `positional-args:"yes" positional-arg-name:"folder-path" description:"Path to folder with image files" required:"yes"`
+		OutputDir string `short:"o" long:"output" description:"Directory to write thumbnails to" default:"thumbnails"`
+		Verbose   bool   `short:"v" long:"verbose" description:"Output more informationm, including warnings"`
+		FastFile  bool   `long:"fast-io" description:"Use memory-mapped io. May be unstable with network paths"`
+	}{}
+)
+
+// ExtractThumbnail reads the embedded Exif thumbnail from the image file with a given path
+func ExtractThumbnail(imageFilePath string, mmap bool) (thumbnail []byte, err error) {
+	defer func() {
+		state := recover()
+		if state != nil {
+			logger.Verbose(2, fmt.Sprintf("Faulted while reading %s: %v", imageFilePath, state))
+			thumbnail = nil
+			err = fmt.Errorf("Faulted while reading %s: %v", imageFilePath, state)
+		}
+	}()
+	f, err := openImageFile(imageFilePath, mmap)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { f.Close() }()
+
+	return exif.ReadThumbnail(f)
+}
+
+// thumbnail path mirrors the location of the image relative to the scanned folder
+func thumbnailPath(folderPath string, imagePath string) string {
+	relPath, err := filepath.Rel(folderPath, imagePath)
+	if err != nil {
+		relPath = filepath.Base(imagePath)
+	}
+	relPath = strings.TrimSuffix(relPath, filepath.Ext(relPath)) + "_thumb.jpg"
+	return filepath.Join(thumbnailOptions.OutputDir, relPath)
+}
+
+func writeThumbnails(wg *sync.WaitGroup, images chan ImageFile) {
+	defer wg.Done()
+	for image := range images {
+		path := image.Path
+		thumbnail, err := ExtractThumbnail(path, thumbnailOptions.FastFile)
+		if err != nil {
+			logger.Verbose(1, fmt.Sprintf("\nFailed to extract thumbnail from '%s': %s", path, err))
+			continue
+		}
+		if thumbnail == nil {
+			logger.Verbose(1, fmt.Sprintf("\nNo thumbnail in '%s'", path))
+			continue
+		}
+		outPath := thumbnailPath(thumbnailOptions.Args.FolderPath, path)
+		err = os.MkdirAll(filepath.Dir(outPath), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(outPath, thumbnail, 0644)
+		}
+		if err != nil {
+			logger.Verbose(1, fmt.Sprintf("\nFailed to write thumbnail '%s': %s", outPath, err))
+		}
+	}
+}
+
+// runThumbnails implements "exif-stat thumbnails" mode which stores embedded thumbnails of all the scanned images
+func runThumbnails(args []string) {
+	_, err := flags.ParseArgs(thumbnailOptions, args)
+
+	if err != nil {
+		os.Exit(-1)
+	}
+	if thumbnailOptions.Verbose {
+		logger.SetVerbosityLevel(1)
+	} else {
+		logger.SetVerbosityLevel(0)
+	}
+
+	var wg sync.WaitGroup
+	images := make(chan ImageFile)
+
+	wg.Add(2)
+	go ListImages(thumbnailOptions.Args.FolderPath, &wg, images)
+	go writeThumbnails(&wg, images)
+
+	wg.Wait()
+
+	fmt.Println("\n100% Done\x1b[0K")
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+// tiffEntry describes an IFD entry of the synthetic Exif used in tests. Values in entries are always big endian
+type tiffEntry struct {
+	ID    uint16
+	Type  uint16
+	Count uint32
+	Data  []byte
+}
+
+// entryData returns entry value in the given byte order
+func entryData(entry tiffEntry, order binary.ByteOrder) []byte {
+	size := 1
+	switch entry.Type {
+	case exif.TypeUnsignedShort, exif.TypeSignedShort:
+		size = 2
+	case exif.TypeUnsignedLong, exif.TypeSignedLong, exif.TypeUnsignedRational, exif.TypeSignedRational:
+		size = 4
+	}
+	if size == 1 || order == binary.BigEndian {
+		return entry.Data
+	}
+	data := make([]byte, len(entry.Data))
+	for i := 0; i+size <= len(data); i += size {
+		for j := 0; j < size; j++ {
+			data[i+j] = entry.Data[i+size-1-j]
+		}
+	}
+	return data
+}
+
+// buildIfd creates IFD followed by the values which do not fit into entries. base is the offset of the IFD from
+// the origin of the offsets
+func buildIfd(order binary.ByteOrder, base uint32, entries ...tiffEntry) []byte {
+	var ifd, values bytes.Buffer
+	valuesStart := base + 2 + 12*uint32(len(entries)) + 4
+	binary.Write(&ifd, order, uint16(len(entries)))
+	for _, entry := range entries {
+		binary.Write(&ifd, order, entry.ID)
+		binary.Write(&ifd, order, entry.Type)
+		binary.Write(&ifd, order, entry.Count)
+		data := entryData(entry, order)
+		if len(data) > 4 {
+			binary.Write(&ifd, order, valuesStart+uint32(values.Len()))
+			values.Write(data)
+			if values.Len()%2 != 0 {
+				values.WriteByte(0)
+			}
+		} else {
+			value := make([]byte, 4)
+			copy(value, data)
+			ifd.Write(value)
+		}
+	}
+	binary.Write(&ifd, order, uint32(0))
+	ifd.Write(values.Bytes())
+	return ifd.Bytes()
+}
+
+// buildMakerNote creates maker notes with the given header followed by IFD. base is the offset of maker notes from
+// the origin of their offsets, which is either the main TIFF header or the start of maker notes
+func buildMakerNote(order binary.ByteOrder, base uint32, header []byte, entries ...tiffEntry) []byte {
+	return append(append([]byte{}, header...), buildIfd(order, base+uint32(len(header)), entries...)...)
+}
+
+// buildMakerNoteJpeg creates a minimal JPEG file with big endian Exif of the camera with the given make and model.
+// Maker notes are created by the function which receives their offset from the TIFF header
+func buildMakerNoteJpeg(cameraMake string, model string, makerNote func(offset uint32) []byte) []byte {
+	ifd0Entries := []tiffEntry{asciiEntry(0x010f, cameraMake), asciiEntry(0x0110, model), longEntry(0x8769, 0)}
+	exifOffset := 8 + uint32(len(buildIfd(binary.BigEndian, 8, ifd0Entries...)))
+	ifd0Entries[2] = longEntry(0x8769, exifOffset)
+	// maker notes are the only value of Exif IFD, they are written right after the IFD
+	data := makerNote(exifOffset + 2 + 12 + 4)
+
+	var tiff bytes.Buffer
+	tiff.Write([]byte{'M', 'M', 0, 0x2A, 0, 0, 0, 8})
+	tiff.Write(buildIfd(binary.BigEndian, 8, ifd0Entries...))
+	tiff.Write(buildIfd(binary.BigEndian, exifOffset, undefinedEntry(0x927c, data)))
+	return buildExifJpeg(tiff.Bytes())
+}
+
+// buildIfd0Jpeg creates a minimal JPEG file with big endian Exif which has only IFD0 with the given entries
+func buildIfd0Jpeg(entries ...tiffEntry) []byte {
+	tiff := append([]byte{'M', 'M', 0, 0x2A, 0, 0, 0, 8}, buildIfd(binary.BigEndian, 8, entries...)...)
+	return buildExifJpeg(tiff)
+}
+
+// buildExifJpeg wraps TIFF structure into APP1 segment of a minimal JPEG file
+func buildExifJpeg(tiff []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0xFF, 0xD8, 0xFF, 0xE1})
+	binary.Write(&buf, binary.BigEndian, uint16(len(tiff)+8))
+	buf.Write([]byte{'E', 'x', 'i', 'f', 0, 0})
+	buf.Write(tiff)
+	buf.Write([]byte{0xFF, 0xDA, 0x00, 0x02, 0xFF, 0xD9})
+	return buf.Bytes()
+}
+
+func asciiEntry(id uint16, value string) tiffEntry {
+	data := append([]byte(value), 0)
+	return tiffEntry{ID: id, Type: exif.TypeASCIItring, Count: uint32(len(data)), Data: data}
+}
+
+func byteEntry(id uint16, values ...byte) tiffEntry {
+	return tiffEntry{ID: id, Type: exif.TypeUnsignedByte, Count: uint32(len(values)), Data: values}
+}
+
+func undefinedEntry(id uint16, data []byte) tiffEntry {
+	return tiffEntry{ID: id, Type: exif.TypeUndefined, Count: uint32(len(data)), Data: data}
+}
+
+func shortEntry(id uint16, values ...uint16) tiffEntry {
+	data := make([]byte, len(values)*2)
+	for i, v := range values {
+		binary.BigEndian.PutUint16(data[i*2:], v)
+	}
+	return tiffEntry{ID: id, Type: exif.TypeUnsignedShort, Count: uint32(len(values)), Data: data}
+}
+
+func longEntry(id uint16, values ...uint32) tiffEntry {
+	data := make([]byte, len(values)*4)
+	for i, v := range values {
+		binary.BigEndian.PutUint32(data[i*4:], v)
+	}
+	return tiffEntry{ID: id, Type: exif.TypeUnsignedLong, Count: uint32(len(values)), Data: data}
+}
+
+func signedLongEntry(id uint16, values ...int32) tiffEntry {
+	data := make([]byte, len(values)*4)
+	for i, v := range values {
+		binary.BigEndian.PutUint32(data[i*4:], uint32(v))
+	}
+	return tiffEntry{ID: id, Type: exif.TypeSignedLong, Count: uint32(len(values)), Data: data}
+}
+
+func signedShortEntry(id uint16, values ...int16) tiffEntry {
+	data := make([]byte, len(values)*2)
+	for i, v := range values {
+		binary.BigEndian.PutUint16(data[i*2:], uint16(v))
+	}
+	return tiffEntry{ID: id, Type: exif.TypeSignedShort, Count: uint32(len(values)), Data: data}
+}
+
+func rationalEntry(id uint16, values ...uint32) tiffEntry {
+	data := make([]byte, len(values)*4)
+	for i, v := range values {
+		binary.BigEndian.PutUint32(data[i*4:], v)
+	}
+	return tiffEntry{ID: id, Type: exif.TypeUnsignedRational, Count: uint32(len(values) / 2), Data: data}
+}
+
+// readTestExif writes file data into a temporary file and extracts exif information from it
+func readTestExif(t *testing.T, data []byte) *ExifInfo {
+	f, err := ioutil.TempFile("", "exif-stat-*.jpg")
+	if err != nil {
+		t.Fatalf("Failed to create temporary file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(data)
+	f.Close()
+	if err != nil {
+		t.Fatalf("Failed to write temporary file: %v", err)
+	}
+	exifInfo, err := ExtractExif(f.Name(), "", false)
+	if err != nil {
+		t.Fatalf("Failed to extract exif: %v", err)
+	}
+	return exifInfo
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+const (
+	xmpMake                    = "tiff:Make"
+	xmpModel                   = "tiff:Model"
+	xmpDateTimeOriginal        = "exif:DateTimeOriginal"
+	xmpIsoSpeedRatings         = "exif:ISOSpeedRatings"
+	xmpPhotographicSensitivity = "exifEX:PhotographicSensitivity"
+	xmpFNumber                 = "exif:FNumber"
+	xmpExposureTime            = "exif:ExposureTime"
+	xmpFocalLength             = "exif:FocalLength"
+	xmpFocalLength35           = "exif:FocalLengthIn35mmFilm"
+	xmpLensMake                = "exifEX:LensMake"
+	xmpLensModel               = "exifEX:LensModel"
+	xmpAuxLens                 = "aux:Lens"
+	xmpRating                  = "xmp:Rating"
+	xmpSubject                 = "dc:subject"
+)
+
+// XMP dates are ISO 8601 with optional seconds, fractions and time zone
+var xmpDateLayouts = []string{
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+}
+
+// parseXmpDate returns local time of the XMP date as UTC, the same way Exif timestamps are reported
+func parseXmpDate(value string) (string, bool) {
+	for _, layout := range xmpDateLayouts {
+		tm, err := time.Parse(layout, value)
+		if err == nil {
+			wallTime := time.Date(tm.Year(), tm.Month(), tm.Day(), tm.Hour(), tm.Minute(), tm.Second(), 0, time.UTC)
+			return wallTime.Format(time.RFC3339), true
+		}
+	}
+	return "", false
+}
+
+func xmpRational(xmp exif.XmpProperties, name string) (exif.Rational, bool) {
+	value, ok := xmp.Get(name)
+	if !ok {
+		return exif.Rational{}, false
+	}
+	rational, err := exif.ParseRational(value)
+	return rational, err == nil && rational.Denominator != 0
+}
+
+func xmpUint16(xmp exif.XmpProperties, names ...string) (uint16, bool) {
+	for _, name := range names {
+		if value, ok := xmp.Get(name); ok {
+			number, err := strconv.ParseUint(value, 10, 16)
+			if err == nil {
+				return uint16(number), true
+			}
+		}
+	}
+	return 0, false
+}
+
+func xmpString(xmp exif.XmpProperties, names ...string) (string, bool) {
+	for _, name := range names {
+		if value, ok := xmp.Get(name); ok && len(value) > 0 {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// metadata sources reported in the CSV output. Fields without explicit source are read from Exif
+const (
+	sourceXmp     = "xmp"
+	sourceSidecar = "sidecar"
+)
+
+// precedence of metadata sources, selected with --xmp-precedence option
+const (
+	precedenceEmbedded = "embedded"
+	precedenceSidecar  = "sidecar"
+)
+
+// xmpApplier copies XMP values into ExifInfo. Values replace existing ones only when override is set
+type xmpApplier struct {
+	xmp      exif.XmpProperties
+	exifInfo *ExifInfo
+	source   string
+	override bool
+}
+
+func (a *xmpApplier) canSet(isEmpty bool) bool {
+	return isEmpty || a.override
+}
+
+func (a *xmpApplier) setSource(field string) {
+	if a.exifInfo.Sources == nil {
+		a.exifInfo.Sources = make(map[string]string)
+	}
+	a.exifInfo.Sources[field] = a.source
+}
+
+func (a *xmpApplier) apply() {
+	xmp, exifInfo := a.xmp, a.exifInfo
+	if value, ok := xmpString(xmp, xmpMake); ok && a.canSet(len(exifInfo.Make) == 0) {
+		exifInfo.Make = value
+		a.setSource("Make")
+	}
+	if value, ok := xmpString(xmp, xmpModel); ok && a.canSet(len(exifInfo.Model) == 0) {
+		exifInfo.Model = value
+		a.setSource("Model")
+	}
+	if value, ok := xmp.Get(xmpDateTimeOriginal); ok && a.canSet(len(exifInfo.CreateTime) == 0) {
+		if createTime, ok := parseXmpDate(value); ok {
+			exifInfo.CreateTime = createTime
+			a.setSource("CreateTime")
+		}
+	}
+	if value, ok := xmpUint16(xmp, xmpIsoSpeedRatings, xmpPhotographicSensitivity); ok && a.canSet(exifInfo.Iso == 0) {
+		exifInfo.Iso = value
+		a.setSource("Iso")
+	}
+	if value, ok := xmpRational(xmp, xmpFNumber); ok && a.canSet(exifInfo.FNumber.Denominator == 0) {
+		exifInfo.FNumber = value
+		a.setSource("FNumber")
+	}
+	if value, ok := xmpRational(xmp, xmpExposureTime); ok && a.canSet(exifInfo.ExposureTime.Denominator == 0) {
+		exifInfo.ExposureTime = value
+		a.setSource("ExposureTime")
+	}
+	if value, ok := xmpRational(xmp, xmpFocalLength); ok && a.canSet(exifInfo.FocalLength.Denominator == 0) {
+		exifInfo.FocalLength = value
+		a.setSource("FocalLength")
+	}
+	if value, ok := xmpUint16(xmp, xmpFocalLength35); ok && a.canSet(exifInfo.FocalLength35 == 0) {
+		exifInfo.FocalLength35 = value
+		a.setSource("FocalLength35")
+	}
+	if value, ok := xmpString(xmp, xmpLensMake); ok && a.canSet(len(exifInfo.LensMake) == 0) {
+		exifInfo.LensMake = value
+		a.setSource("LensMake")
+	}
+	if value, ok := xmpString(xmp, xmpAuxLens, xmpLensModel); ok && a.canSet(len(exifInfo.LensModel) == 0) {
+		exifInfo.LensModel = value
+		a.setSource("LensModel")
+	}
+	if value, ok := xmp.Get(xmpRating); ok && a.canSet(exifInfo.Rating == 0) {
+		if rating, err := strconv.Atoi(value); err == nil {
+			exifInfo.Rating = rating
+			a.setSource("Rating")
+		}
+	}
+	// keywords from the source with precedence replace the existing ones, otherwise the missing keywords are added
+	if keywords, ok := a.xmp[xmpSubject]; ok && len(keywords) > 0 {
+		if a.override {
+			exifInfo.Keywords = nil
+		}
+		count := len(exifInfo.Keywords)
+		exifInfo.Keywords = appendKeywords(exifInfo.Keywords, keywords...)
+		if len(exifInfo.Keywords) > count {
+			a.setSource("Keywords")
+		}
+	}
+}
+
+// applyXmp fills the values missing from the binary Exif with the values from XMP properties
+func applyXmp(xmp exif.XmpProperties, exifInfo *ExifInfo) {
+	if len(xmp) == 0 {
+		return
+	}
+	applier := &xmpApplier{xmp: xmp, exifInfo: exifInfo, source: sourceXmp}
+	applier.apply()
+}
+
+// readSidecar reads XMP properties from the sidecar file
+func readSidecar(sidecarPath string) (exif.XmpProperties, error) {
+	data, err := ioutil.ReadFile(sidecarPath)
+	if err != nil {
+		return nil, err
+	}
+	xmp, err := exif.ParseXmp(data)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid XMP sidecar %s: %v", sidecarPath, err)
+	}
+	return xmp, nil
+}
+
+// applySidecar merges values of XMP sidecar into ExifInfo. Sidecar values replace embedded metadata if sidecar
+// precedence is selected, otherwise they only fill the missing values
+func applySidecar(xmp exif.XmpProperties, precedence string, exifInfo *ExifInfo) {
+	if len(xmp) == 0 {
+		return
+	}
+	applier := &xmpApplier{xmp: xmp, exifInfo: exifInfo, source: sourceSidecar, override: precedence == precedenceSidecar}
+	applier.apply()
+}
+
+// sourcesAsString lists fields which were not read from Exif with their sources, i.e. "LensModel:sidecar;Rating:xmp"
+func (ei *ExifInfo) sourcesAsString() string {
+	fields := make([]string, 0, len(ei.Sources))
+	for field := range ei.Sources {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for i, field := range fields {
+		fields[i] = field + ":" + ei.Sources[field]
+	}
+	return strings.Join(fields, ";")
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/uaraven/exif-stat/exif"
+)
+
+const testSidecar = `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+ <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Description rdf:about=""
+    xmlns:tiff="http://ns.adobe.com/tiff/1.0/"
+    xmlns:exif="http://ns.adobe.com/exif/1.0/"
+    xmlns:xmp="http://ns.adobe.com/xap/1.0/"
+    tiff:Make="Canon"
+    tiff:Model="Canon EOS R6"
+    exif:FocalLength="50/1"
+    xmp:Rating="4"/>
+ </rdf:RDF>
+</x:xmpmeta>`
+
+func TestSidecarIsPostProcessed(t *testing.T) {
+	sidecar, err := exif.ParseXmp([]byte(testSidecar))
+	if err != nil {
+		t.Fatalf("Failed to parse sidecar: %v", err)
+	}
+	options.XmpPrecedence = precedenceSidecar
+	exifInfo := metadataToExifInfo(&exif.Metadata{}, sidecar, &ExifInfo{})
+	if exifInfo.Model != "Canon EOS R6" || exifInfo.Rating != 4 {
+		t.Fatalf("Sidecar values were not applied")
+	}
+	if exifInfo.FocalLength35 != 50 {
+		t.Fatalf("35mm focal length must be calculated from sidecar values, got %d", exifInfo.FocalLength35)
+	}
+	if exifInfo.Sources["FocalLength"] != sourceSidecar {
+		t.Fatalf("Source of the focal length must be sidecar")
+	}
+}
+
+const testKeywordsSidecar = `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+ <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/">
+   <dc:subject>
+    <rdf:Bag>
+     <rdf:li>mountains</rdf:li>
+     <rdf:li>travel</rdf:li>
+    </rdf:Bag>
+   </dc:subject>
+  </rdf:Description>
+ </rdf:RDF>
+</x:xmpmeta>`
+
+func TestSidecarKeywords(t *testing.T) {
+	sidecar, err := exif.ParseXmp([]byte(testKeywordsSidecar))
+	if err != nil {
+		t.Fatalf("Failed to parse sidecar: %v", err)
+	}
+	tests := []struct {
+		precedence string
+		keywords   []string
+		expected   string
+		source     string
+	}{
+		{precedenceSidecar, []string{"travel", "sea"}, "mountains;travel", sourceSidecar},
+		{precedenceEmbedded, []string{"travel", "sea"}, "travel;sea;mountains", sourceSidecar},
+		{precedenceEmbedded, []string{"mountains", "travel"}, "mountains;travel", ""},
+		{precedenceEmbedded, nil, "mountains;travel", sourceSidecar},
+	}
+	for _, test := range tests {
+		exifInfo := &ExifInfo{Keywords: test.keywords}
+		applySidecar(sidecar, test.precedence, exifInfo)
+		if keywords := strings.Join(exifInfo.Keywords, ";"); keywords != test.expected {
+			t.Errorf("Keywords with %s precedence: %s != %s", test.precedence, keywords, test.expected)
+		}
+		if exifInfo.Sources["Keywords"] != test.source {
+			t.Errorf("Source of keywords with %s precedence: '%s' != '%s'", test.precedence,
+				exifInfo.Sources["Keywords"], test.source)
+		}
+	}
+}